@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/AyakuraYuki/go-starrail-compass/ng"
+)
+
+// runAnalyze 分析罗盘是否有解，无解时说明原因
+func runAnalyze(env *environment, args []string) int {
+	opts := &options{}
+	fs := env.newFlagSet("analyze", opts, false)
+	rest, code, ok := parseFlags(fs, args)
+	if !ok {
+		return code
+	}
+
+	compass, err := env.readCompass(rest)
+	if err != nil {
+		return env.fail(exitUsage, "%v", err)
+	}
+	report, err := ng.Analyze(compass)
+	if err != nil {
+		return env.fail(exitUsage, "%v", err)
+	}
+
+	code = exitOK
+	if !report.Solvable {
+		code = exitFailure
+	}
+	if opts.json {
+		if c := env.writeJSON(report); c != exitOK {
+			return c
+		}
+		return code
+	}
+	fmt.Fprintf(env.stdout, "compass: %s\n", compass.String())
+	fmt.Fprintln(env.stdout, report.String())
+	switch {
+	case report.Constrained:
+		fmt.Fprintln(env.stdout, "Please check whether the constraints were entered correctly.")
+	case !report.Solvable:
+		fmt.Fprintln(env.stdout, "Please check whether the locations and speeds of the rings were entered correctly.")
+	}
+	return code
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/AyakuraYuki/go-starrail-compass/ng/catalog"
+)
+
+// runCatalog 列出内置的谜题，给出 ID 时输出该谜题的罗盘信息表达式，可以通过管道交给其他子命令
+func runCatalog(env *environment, args []string) int {
+	opts := &options{}
+	fs := env.newFlagSet("catalog", opts, false)
+	fs.Usage = func() {
+		fmt.Fprintf(env.stderr, "usage: compass catalog [flags] [id]\n\nflags:\n")
+		fs.PrintDefaults()
+	}
+	rest, code, ok := parseFlags(fs, args)
+	if !ok {
+		return code
+	}
+
+	c := catalog.Default()
+	if len(rest) > 0 {
+		entry, ok := c.Lookup(rest[0])
+		if !ok {
+			return env.fail(exitUsage, "unknown puzzle %q", rest[0])
+		}
+		if opts.json {
+			return env.writeJSON(entry)
+		}
+		fmt.Fprintln(env.stdout, entry.Expression)
+		return exitOK
+	}
+
+	entries := c.All()
+	if opts.json {
+		return env.writeJSON(entries)
+	}
+	tw := tabwriter.NewWriter(env.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "id\texpression\tsolution")
+	for _, entry := range entries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", entry.ID, entry.Expression, entry.Solution)
+	}
+	if err := tw.Flush(); err != nil {
+		return env.fail(exitError, "write output error: %v", err)
+	}
+	return exitOK
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/AyakuraYuki/go-starrail-compass/ng"
+)
+
+// checkJSON check 子命令的 JSON 输出
+type checkJSON struct {
+	Compass string        `json:"compass"`
+	Steps   []ng.StepJSON `json:"steps"`
+	Solved  bool          `json:"solved"`
+	// 解谜步骤违反 Compass.Constraints 时的原因
+	Violation string `json:"violation,omitempty"`
+}
+
+// runCheck 检查解谜步骤能否完成罗盘
+func runCheck(env *environment, args []string) int {
+	opts := &options{}
+	fs := env.newFlagSet("check", opts, false)
+	stepsExpr := fs.String("steps", "", `steps to check, e.g. "mi3,om1" (required)`)
+	rest, code, ok := parseFlags(fs, args)
+	if !ok {
+		return code
+	}
+	if !isFlagSet(fs, "steps") {
+		fs.Usage()
+		return env.fail(exitUsage, "missing -steps")
+	}
+
+	compass, err := env.readCompass(rest)
+	if err != nil {
+		return env.fail(exitUsage, "%v", err)
+	}
+	steps, err := ng.ParseSteps(*stepsExpr, len(compass.Rings))
+	if err != nil {
+		return env.fail(exitUsage, "%v", err)
+	}
+
+	solved, err := ng.CheckSolution(compass, steps)
+	var violation *ng.ConstraintViolation
+	if err != nil && !errors.As(err, &violation) {
+		return env.fail(exitUsage, "%v", err)
+	}
+
+	code = exitOK
+	if !solved {
+		code = exitFailure
+	}
+	if opts.json {
+		result := checkJSON{Compass: compass.String(), Steps: ng.NewStepsJSON(compass, steps), Solved: solved}
+		if violation != nil {
+			result.Violation = violation.Error()
+		}
+		if c := env.writeJSON(result); c != exitOK {
+			return c
+		}
+		return code
+	}
+	switch {
+	case solved:
+		fmt.Fprintf(env.stdout, "%s solves %s\n", formatSteps(compass, steps), compass.String())
+	case violation != nil:
+		fmt.Fprintf(env.stdout, "%s does not solve %s: %v\n", formatSteps(compass, steps), compass.String(), violation)
+	default:
+		fmt.Fprintf(env.stdout, "%s does not solve %s\n", formatSteps(compass, steps), compass.String())
+	}
+	return code
+}
+
+// isFlagSet 判断参数是否在命令行中给出
+func isFlagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// readSteps 读取 -steps 给出的解谜步骤，未给出时使用求解器求解
+func (env *environment) readSteps(fs *flag.FlagSet, opts *options, expression string, compass ng.Compass) (ng.Steps, int) {
+	if !isFlagSet(fs, "steps") {
+		return env.solve(opts, compass)
+	}
+	steps, err := ng.ParseSteps(expression, len(compass.Rings))
+	if err != nil {
+		return nil, env.fail(exitUsage, "%v", err)
+	}
+	for _, step := range steps {
+		if !compass.IsRingGroupSupported(step.RingGroup) {
+			return nil, env.fail(exitUsage, "ring group %s is not supported by the compass", step.RingGroup.Format(len(compass.Rings)))
+		}
+	}
+	return steps, exitOK
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AyakuraYuki/go-starrail-compass/ng"
+)
+
+// explainJSON explain 子命令的 JSON 输出
+type explainJSON struct {
+	Compass     string          `json:"compass"`
+	Solution    ng.SolutionJSON `json:"solution"` // Steps 保留转动的顺序
+	Explanation []string        `json:"explanation"`
+	Solved      bool            `json:"solved"`
+	// 解谜步骤违反 Compass.Constraints 时的原因
+	Violation string `json:"violation,omitempty"`
+}
+
+// runExplain 用文字描述罗盘以及解法
+func runExplain(env *environment, args []string) int {
+	opts := &options{}
+	fs := env.newFlagSet("explain", opts, true)
+	stepsExpr := fs.String("steps", "", `steps to explain in order, e.g. "mi3,om1" (default: the optimal solution)`)
+	rest, code, ok := parseFlags(fs, args)
+	if !ok {
+		return code
+	}
+
+	compass, err := env.readCompass(rest)
+	if err != nil {
+		return env.fail(exitUsage, "%v", err)
+	}
+	steps, code := env.readSteps(fs, opts, *stepsExpr, compass)
+	if code != exitOK {
+		return code
+	}
+
+	// -steps 给出的步骤不一定能完成罗盘，与 check 子命令一样检查并以退出码表示结果
+	solved, err := ng.CheckSolution(compass, steps)
+	var violation *ng.ConstraintViolation
+	if err != nil && !errors.As(err, &violation) {
+		return env.fail(exitUsage, "%v", err)
+	}
+	code = exitOK
+	if !solved {
+		code = exitFailure
+	}
+
+	lines := explain(compass, steps)
+	switch {
+	case violation != nil:
+		lines = append(lines, fmt.Sprintf("These steps do not solve the compass: %v.", violation))
+	case !solved:
+		lines = append(lines, "These steps do not solve the compass.")
+	}
+	if opts.json {
+		result := explainJSON{
+			Compass:     compass.String(),
+			Solution:    ng.NewSolutionJSON(compass, steps),
+			Explanation: lines,
+			Solved:      solved,
+		}
+		if violation != nil {
+			result.Violation = violation.Error()
+		}
+		if c := env.writeJSON(result); c != exitOK {
+			return c
+		}
+		return code
+	}
+	for _, line := range lines {
+		fmt.Fprintln(env.stdout, line)
+	}
+	return code
+}
+
+// explain 生成罗盘以及解法的文字描述
+func explain(compass ng.Compass, steps ng.Steps) []string {
+	scales := compass.ScaleCount()
+	rings := len(compass.Rings)
+	degrees := func(notches int) string {
+		return fmt.Sprintf("%g°", float64(ng.Mod(notches, scales))*360/float64(scales))
+	}
+	turn := func(move int) string {
+		direction := "clockwise"
+		if move < 0 {
+			direction = "counterclockwise"
+		}
+		return fmt.Sprintf("%g° %s", float64(ng.Abs(move))*360/float64(scales), direction)
+	}
+
+	lines := []string{
+		fmt.Sprintf("Compass %s has %d rings and %d notches of %g° each.", compass.String(), len(compass.Rings), scales, 360/float64(scales)),
+	}
+	for i := len(compass.Rings) - 1; i >= 0; i-- {
+		ring := compass.Rings[i]
+		lines = append(lines, fmt.Sprintf("  The %s ring starts at %s and must end at %s.", ng.RingName(i, rings), degrees(ring.Location), degrees(ring.Target)))
+	}
+
+	lines = append(lines, "Ring groups:")
+	for _, rg := range compass.RingGroups {
+		movement := compass.Movement(rg)
+		turns := make([]string, 0, len(movement))
+		for i := len(movement) - 1; i >= 0; i-- {
+			if movement[i] != 0 {
+				turns = append(turns, fmt.Sprintf("the %s ring %s", ng.RingName(i, rings), turn(movement[i])))
+			}
+		}
+		lines = append(lines, fmt.Sprintf("  %s turns %s.", rg.Format(len(compass.Rings)), strings.Join(turns, " and ")))
+	}
+
+	if steps.Total() == 0 {
+		return append(lines, "The compass is already solved, no press is needed.")
+	}
+	lines = append(lines, fmt.Sprintf("Solution %s (%s):", formatSteps(compass, steps), formatPresses(steps.Total())))
+
+	locations := make([]int, len(compass.Rings))
+	for i, ring := range compass.Rings {
+		locations[i] = ring.Location
+	}
+	index := 0
+	for _, step := range steps {
+		if step.Count <= 0 {
+			continue
+		}
+		index++
+		movement := compass.Movement(step.RingGroup)
+		changes := make([]string, 0, len(movement))
+		for i := len(movement) - 1; i >= 0; i-- {
+			if movement[i] == 0 {
+				continue
+			}
+			next := locations[i] + step.Count*movement[i]
+			changes = append(changes, fmt.Sprintf("%s ring %s → %s", ng.RingName(i, rings), degrees(locations[i]), degrees(next)))
+			locations[i] = next
+		}
+		lines = append(lines, fmt.Sprintf("  %d. Press %s %s: %s.", index, step.RingGroup.Format(len(compass.Rings)), pluralTimes(step.Count), strings.Join(changes, ", ")))
+	}
+	return lines
+}
+
+// pluralTimes 返回次数的文字表述
+func pluralTimes(n int) string {
+	if n == 1 {
+		return "once"
+	}
+	return fmt.Sprintf("%d times", n)
+}
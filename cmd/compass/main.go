@@ -0,0 +1,236 @@
+// Command compass 在命令行中求解《崩坏：星穹铁道》的引航罗盘谜题
+//
+// 用法:
+//
+//	compass <command> [flags] [expression]
+//
+// 罗盘信息表达式的格式参见 ng.ParseCompass，例如 0+2,3-3,0+3/mi,om,oi，
+// 未在参数中给出或者给出 - 时从标准输入读取；带有限制的罗盘可以给出 ng.Compass 的 JSON 对象
+//
+// 退出码:
+//
+//	0 成功
+//	1 罗盘无解，或者解谜步骤没有通过检查
+//	2 参数或表达式有误
+//	3 求解过程出错，例如超时
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bombsimon/logrusr/v4"
+	"github.com/go-logr/logr"
+	"github.com/sirupsen/logrus"
+
+	"github.com/AyakuraYuki/go-starrail-compass/ng"
+)
+
+// 退出码
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+	exitError   = 3
+)
+
+// command 子命令
+type command struct {
+	name        string
+	description string
+	run         func(env *environment, args []string) int
+}
+
+// commands 全部子命令，按名称排列
+var commands = []command{
+	{"analyze", "tell whether the compass is solvable and why not", runAnalyze},
+	{"catalog", "list the built-in puzzles or print one by id", runCatalog},
+	{"check", "check whether the steps solve the compass", runCheck},
+	{"explain", "describe the compass and its solution in plain words", runExplain},
+	{"serve", "serve the solvers over an HTTP JSON API", runServe},
+	{"solvers", "list the registered solvers", runSolvers},
+	{"simulate", "print ring locations after every single press", runSimulate},
+	{"solve", "find the optimal solution of the compass", runSolve},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// environment 子命令的运行环境
+type environment struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// run 执行命令行，返回退出码
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	env := &environment{stdin: stdin, stdout: stdout, stderr: stderr}
+	if len(args) == 0 {
+		env.usage()
+		return exitUsage
+	}
+
+	name := args[0]
+	if name == "help" || name == "-h" || name == "-help" || name == "--help" {
+		env.usage()
+		return exitOK
+	}
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd.run(env, args[1:])
+		}
+	}
+
+	fmt.Fprintf(stderr, "compass: unknown command %q\n", name)
+	env.usage()
+	return exitUsage
+}
+
+// usage 输出帮助信息
+func (env *environment) usage() {
+	fmt.Fprintln(env.stderr, "usage: compass <command> [flags] [expression]")
+	fmt.Fprintln(env.stderr)
+	fmt.Fprintln(env.stderr, "commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(env.stderr, "  %-10s %s\n", cmd.name, cmd.description)
+	}
+	fmt.Fprintln(env.stderr)
+	fmt.Fprintln(env.stderr, "The expression is read from stdin when omitted or given as \"-\".")
+	fmt.Fprintln(env.stderr, "Run \"compass <command> -h\" for the flags of a command.")
+}
+
+// fail 输出错误信息并返回退出码
+func (env *environment) fail(code int, format string, args ...any) int {
+	fmt.Fprintf(env.stderr, "compass: "+format+"\n", args...)
+	return code
+}
+
+// options 子命令共用的参数
+type options struct {
+	json    bool
+	solver  string
+	cost    string
+	timeout time.Duration
+	verbose int
+}
+
+// newFlagSet 创建子命令的参数集合，withSolver 为 true 时注册求解器相关的参数
+func (env *environment) newFlagSet(name string, opts *options, withSolver bool) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(env.stderr)
+	fs.BoolVar(&opts.json, "json", false, "print JSON instead of human-readable text")
+	if withSolver {
+		fs.StringVar(&opts.solver, "solver", "", fmt.Sprintf("solver to use: %s (default %s, or %s for compasses with constraints)",
+			strings.Join(ng.SolverNames(), ", "), ng.DefaultSolverName, ng.ConstrainedSolverName))
+		fs.StringVar(&opts.cost, "cost", ng.DefaultCostName, "cost to minimize: "+strings.Join(ng.CostFuncNames(), ", "))
+		fs.DurationVar(&opts.timeout, "timeout", 10*time.Second, "give up solving after this duration")
+		fs.IntVar(&opts.verbose, "v", 0, "log verbosity of the solver")
+	}
+	fs.Usage = func() {
+		fmt.Fprintf(env.stderr, "usage: compass %s [flags] [expression]\n\nflags:\n", name)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags 解析参数，返回剩余的位置参数；无法继续执行时 ok 为 false，并返回应当使用的退出码
+func parseFlags(fs *flag.FlagSet, args []string) (rest []string, code int, ok bool) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, exitOK, false
+		}
+		return nil, exitUsage, false
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return nil, exitUsage, false
+	}
+	return fs.Args(), exitOK, true
+}
+
+// readCompass 从位置参数或者标准输入读取并解析罗盘信息表达式，也可以是 Compass 的 JSON 对象
+func (env *environment) readCompass(args []string) (ng.Compass, error) {
+	expression := "-"
+	if len(args) > 0 {
+		expression = args[0]
+	}
+	if expression == "-" {
+		content, err := io.ReadAll(env.stdin)
+		if err != nil {
+			return ng.Compass{}, fmt.Errorf("read expression from stdin error: %w", err)
+		}
+		expression = string(content)
+	}
+	expression = strings.TrimSpace(expression)
+	if expression == "" {
+		return ng.Compass{}, errors.New("missing compass expression")
+	}
+
+	// 罗盘信息表达式无法表述 Constraints，带有限制的罗盘使用 Compass 的 JSON 对象
+	if strings.HasPrefix(expression, "{") {
+		var compass ng.Compass
+		if err := json.Unmarshal([]byte(expression), &compass); err != nil {
+			return compass, fmt.Errorf("parse compass JSON error: %w", err)
+		}
+		return compass, nil
+	}
+
+	compass, err := ng.ParseCompass(expression)
+	if err != nil {
+		return compass, err
+	}
+	if err := compass.Validate(); err != nil {
+		return compass, fmt.Errorf("invalid compass: %w", err)
+	}
+	return compass, nil
+}
+
+// newSolver 按参数创建求解 compass 的求解器，没有指定 -solver 时使用 ng.DefaultSolverFor 选择的求解器
+func (env *environment) newSolver(opts *options, compass ng.Compass) (ng.Solver, error) {
+	cost, err := ng.CostFuncByName(opts.cost)
+	if err != nil {
+		return nil, err
+	}
+
+	// -v N 开启求解器 V(N) 及以下级别的日志
+	logger := logr.Discard()
+	if opts.verbose > 0 {
+		l := logrus.New()
+		l.SetOutput(env.stderr)
+		l.SetLevel(logrus.Level(min(int(logrus.InfoLevel)+opts.verbose, int(logrus.TraceLevel))))
+		logger = logrusr.New(l)
+	}
+	name := opts.solver
+	if name == "" {
+		name = ng.DefaultSolverFor(compass)
+	}
+	return ng.NewSolver(name, ng.SolverOptions{Logger: logger, Cost: cost})
+}
+
+// solve 求解罗盘，按退出码区分无解与出错
+func (env *environment) solve(opts *options, compass ng.Compass) (ng.Steps, int) {
+	solver, err := env.newSolver(opts, compass)
+	if err != nil {
+		return nil, env.fail(exitUsage, "%v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+	defer cancel()
+	solution, err := solver.Solve(ctx, compass)
+	if errors.Is(err, ng.ErrNoSolution) {
+		return nil, env.fail(exitFailure, "%v (run \"compass analyze\" to see why)", err)
+	}
+	if err != nil {
+		return nil, env.fail(exitError, "solve error: %v", err)
+	}
+	return solution, exitOK
+}
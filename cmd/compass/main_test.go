@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		code     int
+		contains string
+	}{
+		{"solve", []string{"solve", "0+3,3-3,0+2/mi,om,oi"}, "", exitOK, "solution: mi3 (3 presses)"},
+		{"solve from stdin", []string{"solve"}, "0+3,3-3,0+2/mi,om,oi\n", exitOK, "solution: mi3"},
+		{"solve from dash", []string{"solve", "-solver", "hunger", "-"}, "0+3,3-3,0+2/mi,om,oi", exitOK, "solution: mi3"},
+		{"solve all", []string{"solve", "-all", "0+2,4-4,0+1/mi,oi,om"}, "", exitOK, "solutions: 4"},
+		{"solve with cost", []string{"solve", "-cost", "animation", "3+3,0+1,3+1/o,i,oi"}, "", exitOK, "solution: i2,oi1"},
+		{"solve with switches cost", []string{"solve", "-cost", "switches", "3+3,0+1,3+1/o,i,oi"}, "", exitOK, "solution: oi3"},
+		{"solve no solution", []string{"solve", "1+2,0+2,0+2/om,oi,mi"}, "", exitFailure, ""},
+		{"solve unknown solver", []string{"solve", "-solver", "magic", "0+3,3-3,0+2/mi,om,oi"}, "", exitUsage, ""},
+		{"solve unknown cost", []string{"solve", "-cost", "magic", "0+3,3-3,0+2/mi,om,oi"}, "", exitUsage, ""},
+		{"solve invalid expression", []string{"solve", "hello"}, "", exitUsage, ""},
+		{"solve missing expression", []string{"solve"}, "", exitUsage, ""},
+		{"check solved", []string{"check", "-steps", "mi3", "0+3,3-3,0+2/mi,om,oi"}, "", exitOK, "mi3 solves"},
+		{"check not solved", []string{"check", "-steps", "mi2", "0+3,3-3,0+2/mi,om,oi"}, "", exitFailure, "mi2 does not solve"},
+		{"solve constraints", []string{"solve", `{"rings":[{"location":0,"speed":2},{"location":3,"speed":-3},{"location":0,"speed":3}],"ringGroups":["mi","om","oi"],"constraints":{"maxGroupPresses":{"mi":1}}}`}, "", exitOK, "solution: mi1,oi2"},
+		{"solve constraints unsupported solver", []string{"solve", "-solver", "linear", `{"rings":[{"location":0,"speed":2},{"location":3,"speed":-3},{"location":0,"speed":3}],"ringGroups":["mi","om","oi"],"constraints":{"maxGroupPresses":{"mi":1}}}`}, "", exitError, ""},
+		{"check violation", []string{"check", "-steps", "mi3", `{"rings":[{"location":0,"speed":2},{"location":3,"speed":-3},{"location":0,"speed":3}],"ringGroups":["mi","om","oi"],"constraints":{"maxGroupPresses":{"mi":1}}}`}, "", exitFailure, "mi3 does not solve 0+3,3-3,0+2/mi,oi,om: press 2 exceeds"},
+		{"check unsupported group", []string{"check", "-steps", "o1", "0+3,3-3,0+2/mi,om,oi"}, "", exitUsage, ""},
+		{"check missing steps", []string{"check", "0+3,3-3,0+2/mi,om,oi"}, "", exitUsage, ""},
+		{"simulate", []string{"simulate", "0+3,3-3,0+2/mi,om,oi"}, "", exitOK, "solved"},
+		{"simulate not solved", []string{"simulate", "-steps", "om1", "0+3,3-3,0+2/mi,om,oi"}, "", exitFailure, "not solved"},
+		{"explain", []string{"explain", "0+3,3-3,0+2/mi,om,oi"}, "", exitOK, "1. Press mi 3 times: middle ring 180° → 0°, inner ring 0° → 0°."},
+		{"explain solved", []string{"explain", "0+3,0-3,0+2/mi,om,oi"}, "", exitOK, "already solved"},
+		{"explain steps", []string{"explain", "-steps", "mi3", "0+3,3-3,0+2/mi,om,oi"}, "", exitOK, "1. Press mi 3 times"},
+		{"explain steps not solved", []string{"explain", "-steps", "mi2", "0+3,3-3,0+2/mi,om,oi"}, "", exitFailure, "These steps do not solve the compass."},
+		{"explain steps violation", []string{"explain", "-steps", "mi3", `{"rings":[{"location":0,"speed":2},{"location":3,"speed":-3},{"location":0,"speed":3}],"ringGroups":["mi","om","oi"],"constraints":{"maxGroupPresses":{"mi":1}}}`}, "", exitFailure, "These steps do not solve the compass: press 2 exceeds"},
+		{"solvers", []string{"solvers"}, "", exitOK, "linear"},
+		{"solvers json", []string{"solvers", "-json"}, "", exitOK, `"name": "hunger"`},
+		{"serve help", []string{"serve", "-h"}, "", exitOK, ""},
+		{"serve unexpected argument", []string{"serve", "0+3,3-3,0+2/mi,om,oi"}, "", exitUsage, ""},
+		{"analyze", []string{"analyze", "0+3,3-3,0+2/mi,om,oi"}, "", exitOK, "solvable: 18 solutions per period"},
+		{"analyze not solvable", []string{"analyze", "1+3,0+3,0+3/o,m,i"}, "", exitFailure, "every press keeps outer (mod 3) unchanged"},
+		{"simulate violation", []string{"simulate", "-steps", "mi3", `{"rings":[{"location":0,"speed":2},{"location":3,"speed":-3},{"location":0,"speed":3}],"ringGroups":["mi","om","oi"],"constraints":{"maxGroupPresses":{"mi":1}}}`}, "", exitFailure, "not solved: press 2 exceeds the limit of 1 presses for ring group mi"},
+		{"analyze constraints", []string{"analyze", `{"rings":[{"location":0,"speed":2},{"location":3,"speed":-3},{"location":0,"speed":3}],"ringGroups":["mi","om","oi"],"constraints":{"maxPresses":2}}`}, "", exitFailure, "no sequence of presses reaches the targets within the constraints"},
+		{"analyze json", []string{"analyze", "-json", "1+3,0+3,0+3/o,m,i"}, "", exitFailure, `"solvable": false`},
+		{"catalog", []string{"catalog"}, "", exitOK, "readme-defect"},
+		{"catalog entry", []string{"catalog", "readme-defect"}, "", exitOK, "0+3,3-3,0+2/mi,om,oi"},
+		{"catalog unknown entry", []string{"catalog", "magic"}, "", exitUsage, ""},
+		{"help", []string{"help"}, "", exitOK, ""},
+		{"command help", []string{"solve", "-h"}, "", exitOK, ""},
+		{"no command", nil, "", exitUsage, ""},
+		{"unknown command", []string{"magic"}, "", exitUsage, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+			code := run(test.args, strings.NewReader(test.stdin), stdout, stderr)
+			if code != test.code {
+				t.Fatalf("unexpected exit code %d (expected: %d)\nstdout: %s\nstderr: %s", code, test.code, stdout, stderr)
+			}
+			if !strings.Contains(stdout.String(), test.contains) {
+				t.Fatalf("output does not contain %q:\n%s", test.contains, stdout)
+			}
+		})
+	}
+}
+
+func TestRun_JSON(t *testing.T) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	if code := run([]string{"solve", "-json", "0+3,3-3,0+2/mi,om,oi"}, nil, stdout, stderr); code != exitOK {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr)
+	}
+	var solved solveJSON
+	if err := json.Unmarshal(stdout.Bytes(), &solved); err != nil {
+		t.Fatal(err)
+	}
+	if solved.Solution == nil || solved.Solution.Expression != "mi3" || solved.Solution.Presses != 3 {
+		t.Fatalf("unexpected output: %s", stdout)
+	}
+
+	stdout.Reset()
+	if code := run([]string{"simulate", "-json", "-steps", "om1,mi1", "0+3,3-3,0+2/mi,om,oi"}, nil, stdout, stderr); code != exitFailure {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr)
+	}
+	var simulated struct {
+		Frames []struct {
+			RingGroup string `json:"ringGroup"`
+			Locations []int  `json:"locations"`
+		} `json:"frames"`
+		Solved bool `json:"solved"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &simulated); err != nil {
+		t.Fatal(err)
+	}
+	if len(simulated.Frames) != 3 || simulated.Solved {
+		t.Fatalf("unexpected output: %s", stdout)
+	}
+	// 各圈的位置从内到外排列
+	if last := simulated.Frames[2]; last.RingGroup != "mi" || last.Locations[0] != 2 || last.Locations[1] != 3 || last.Locations[2] != 3 {
+		t.Fatalf("unexpected frame: %+v", last)
+	}
+
+	// explain 的解法保留 -steps 给出的转动顺序
+	stdout.Reset()
+	if code := run([]string{"explain", "-json", "-steps", "oi2,mi1", "0+3,3-3,0+2/mi,om,oi"}, nil, stdout, stderr); code != exitOK {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr)
+	}
+	var explained explainJSON
+	if err := json.Unmarshal(stdout.Bytes(), &explained); err != nil {
+		t.Fatal(err)
+	}
+	if steps := explained.Solution.Steps; !explained.Solved || len(steps) != 2 || steps[0].RingGroup != "oi" || steps[1].RingGroup != "mi" {
+		t.Fatalf("unexpected output: %s", stdout)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/AyakuraYuki/go-starrail-compass/ng"
+)
+
+// writeJSON 以缩进格式输出 JSON
+func (env *environment) writeJSON(v any) int {
+	encoder := json.NewEncoder(env.stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return env.fail(exitError, "encode JSON error: %v", err)
+	}
+	return exitOK
+}
+
+// formatSteps 返回解谜步骤的文字表述，保留步骤的顺序
+func formatSteps(compass ng.Compass, steps ng.Steps) string {
+	text := ""
+	for _, step := range steps {
+		if step.Count <= 0 {
+			continue
+		}
+		if text != "" {
+			text += ","
+		}
+		text += step.Format(len(compass.Rings))
+	}
+	if text == "" {
+		return "(none)"
+	}
+	return text
+}
+
+// formatPresses 返回转动次数的文字表述
+func formatPresses(n int) string {
+	if n == 1 {
+		return "1 press"
+	}
+	return fmt.Sprintf("%d presses", n)
+}
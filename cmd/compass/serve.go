@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/bombsimon/logrusr/v4"
+	"github.com/sirupsen/logrus"
+
+	"github.com/AyakuraYuki/go-starrail-compass/ng/server"
+)
+
+// runServe 启动 HTTP JSON API 服务，接口说明参见 ng/server
+func runServe(env *environment, args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(env.stderr)
+	addr := fs.String("addr", "localhost:8080", "address to listen on")
+	timeout := fs.Duration("timeout", server.DefaultTimeout, "default solving timeout of a request")
+	maxTimeout := fs.Duration("max-timeout", server.DefaultMaxTimeout, "longest solving timeout a request may ask for")
+	verbose := fs.Int("v", 0, "log verbosity of the solver")
+	fs.Usage = func() {
+		fmt.Fprintf(env.stderr, "usage: compass serve [flags]\n\nflags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	l := logrus.New()
+	l.SetOutput(env.stderr)
+	l.SetLevel(logrus.Level(min(int(logrus.InfoLevel)+*verbose, int(logrus.TraceLevel))))
+	logger := logrusr.New(l)
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           server.New(server.Options{Logger: logger, Timeout: *timeout, MaxTimeout: *maxTimeout}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	// 收到中断信号后停止接受新的请求，并等待正在处理的请求完成
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), *maxTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			logger.Error(err, "shutdown error")
+		}
+	}()
+
+	logger.Info("listening", "addr", *addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return env.fail(exitError, "serve error: %v", err)
+	}
+	return exitOK
+}
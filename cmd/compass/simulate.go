@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/AyakuraYuki/go-starrail-compass/ng"
+)
+
+// runSimulate 逐次转动罗盘并输出每次转动后各圈的位置
+func runSimulate(env *environment, args []string) int {
+	opts := &options{}
+	fs := env.newFlagSet("simulate", opts, true)
+	stepsExpr := fs.String("steps", "", `steps to simulate in order, e.g. "mi3,om1" (default: the optimal solution)`)
+	rest, code, ok := parseFlags(fs, args)
+	if !ok {
+		return code
+	}
+
+	compass, err := env.readCompass(rest)
+	if err != nil {
+		return env.fail(exitUsage, "%v", err)
+	}
+	steps, code := env.readSteps(fs, opts, *stepsExpr, compass)
+	if code != exitOK {
+		return code
+	}
+
+	trace, err := ng.Simulate(compass, steps)
+	if err != nil {
+		return env.fail(exitUsage, "%v", err)
+	}
+
+	code = exitOK
+	if !trace.Solved() {
+		code = exitFailure
+	}
+	if opts.json {
+		if c := env.writeJSON(trace); c != exitOK {
+			return c
+		}
+		return code
+	}
+
+	fmt.Fprintf(env.stdout, "compass: %s\n", compass.String())
+	fmt.Fprintf(env.stdout, "steps:   %s\n\n", formatSteps(compass, steps))
+	tw := tabwriter.NewWriter(env.stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprint(tw, "press\tgroup\t")
+	rings := len(compass.Rings)
+	for i := rings - 1; i >= 0; i-- {
+		fmt.Fprintf(tw, "%s\t", ng.RingName(i, rings))
+	}
+	fmt.Fprintln(tw)
+	for _, frame := range trace.Frames {
+		group := frame.RingGroup.Format(len(compass.Rings))
+		if frame.Press == 0 {
+			group = "start"
+		}
+		fmt.Fprintf(tw, "%d\t%s\t", frame.Press, group)
+		for i := len(frame.Locations) - 1; i >= 0; i-- {
+			fmt.Fprintf(tw, "%d\t", frame.Locations[i])
+		}
+		fmt.Fprintln(tw)
+	}
+	if err := tw.Flush(); err != nil {
+		return env.fail(exitError, "write output error: %v", err)
+	}
+	switch {
+	case trace.Violation != nil:
+		fmt.Fprintf(env.stdout, "\nnot solved: %v\n", trace.Violation)
+	case trace.Solved():
+		fmt.Fprintln(env.stdout, "\nsolved")
+	default:
+		fmt.Fprintln(env.stdout, "\nnot solved")
+	}
+	return code
+}
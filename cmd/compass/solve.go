@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AyakuraYuki/go-starrail-compass/ng"
+)
+
+// solveJSON solve 子命令的 JSON 输出
+type solveJSON struct {
+	Compass   string            `json:"compass"`
+	Solution  *ng.SolutionJSON  `json:"solution,omitempty"`
+	Solutions []ng.SolutionJSON `json:"solutions,omitempty"`
+}
+
+// runSolve 求解罗盘
+func runSolve(env *environment, args []string) int {
+	opts := &options{}
+	fs := env.newFlagSet("solve", opts, true)
+	all := fs.Bool("all", false, "print every solution in one period instead of the optimal one")
+	rest, code, ok := parseFlags(fs, args)
+	if !ok {
+		return code
+	}
+
+	compass, err := env.readCompass(rest)
+	if err != nil {
+		return env.fail(exitUsage, "%v", err)
+	}
+
+	if *all {
+		return env.solveAll(opts, compass)
+	}
+
+	solution, code := env.solve(opts, compass)
+	if code != exitOK {
+		return code
+	}
+	if opts.json {
+		result := ng.NewSolutionJSON(compass, solution)
+		return env.writeJSON(solveJSON{Compass: compass.String(), Solution: &result})
+	}
+	fmt.Fprintf(env.stdout, "compass:  %s\n", compass.String())
+	fmt.Fprintf(env.stdout, "solution: %s (%s)\n", formatSteps(compass, solution), formatPresses(solution.Total()))
+	return exitOK
+}
+
+// solveAll 求出全部解法
+func (env *environment) solveAll(opts *options, compass ng.Compass) int {
+	solver, err := env.newSolver(opts, compass)
+	if err != nil {
+		return env.fail(exitUsage, "%v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+	defer cancel()
+	solutions, err := solver.AllSolutions(ctx, compass)
+	if err != nil {
+		return env.fail(exitError, "solve error: %v", err)
+	}
+	if len(solutions) == 0 {
+		return env.fail(exitFailure, "%v", ng.ErrNoSolution)
+	}
+
+	if opts.json {
+		result := solveJSON{Compass: compass.String(), Solutions: make([]ng.SolutionJSON, len(solutions))}
+		for i, solution := range solutions {
+			result.Solutions[i] = ng.NewSolutionJSON(compass, solution)
+		}
+		return env.writeJSON(result)
+	}
+	fmt.Fprintf(env.stdout, "compass:   %s\n", compass.String())
+	fmt.Fprintf(env.stdout, "solutions: %d\n", len(solutions))
+	for i, solution := range solutions {
+		fmt.Fprintf(env.stdout, "%5d. %s (%s)\n", i+1, formatSteps(compass, solution), formatPresses(solution.Total()))
+	}
+	return exitOK
+}
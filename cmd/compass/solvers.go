@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/AyakuraYuki/go-starrail-compass/ng"
+)
+
+// runSolvers 列出可以通过 -solver 选择的求解器
+func runSolvers(env *environment, args []string) int {
+	opts := &options{}
+	fs := env.newFlagSet("solvers", opts, false)
+	rest, code, ok := parseFlags(fs, args)
+	if !ok {
+		return code
+	}
+	if len(rest) > 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	solvers := ng.Solvers()
+	if opts.json {
+		return env.writeJSON(solvers)
+	}
+	tw := tabwriter.NewWriter(env.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "name\tcomplete\toptimal\tgeneralized\tordered\tdescription")
+	for _, info := range solvers {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", info.Name, yesNo(info.Complete), yesNo(info.Optimal), yesNo(info.Generalized), yesNo(info.Ordered), info.Description)
+	}
+	if err := tw.Flush(); err != nil {
+		return env.fail(exitError, "write output error: %v", err)
+	}
+	return exitOK
+}
+
+// yesNo 返回布尔值的文字表述
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/AyakuraYuki/go-starrail-compass/ng"
+)
+
+// guess 使用 GaussMatrix 求解，返回全部解法；GaussMatrix 在 mulRow 中 panic 时返回错误
+// mulRow 在 panic 前会向标准输出打印矩阵，调用方需要自行屏蔽
+func guess(matrix [][]int) (result [][]int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return NewGaussMatrix(matrix, MOD).Guess(), nil
+}
+
+// TestGaussMatrix_README 将 README 中的矩阵转换为罗盘，检查 GaussMatrix 给出的解法
+func TestGaussMatrix_README(t *testing.T) {
+	matrix := [][]int{
+		{-1, 0, -1, MOD - 4},
+		{-3, -3, 0, MOD - 0},
+		{0, 3, 3, MOD - 0},
+	}
+	compass, err := ng.CompassFromMatrix(matrix, MOD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if compass.String() != "0+3,0-3,4-1/mi,oi,om" {
+		t.Fatalf("unexpected compass %s", compass.String())
+	}
+	result := NewGaussMatrix(compass.ToMatrix(), MOD).Guess()
+	if len(result) == 0 {
+		t.Fatal("expected a solution")
+	}
+	steps, err := ng.StepsFromCounts(compass, result[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := ng.CheckSolution(compass, steps); err != nil || !ok || steps.String() != "mi4" {
+		t.Fatalf("unexpected solution %s: %v", steps.String(), err)
+	}
+}
+
+// TestGaussMatrix_Differential 遍历全部三圈罗盘与方案组合，将 GaussMatrix 与 ng 的线性代数求解器对比
+// GaussMatrix 给出的每一个解法都必须正确；GaussMatrix 漏解的罗盘（README 中记录的缺陷）只统计数量
+// 完整遍历约 220 万个罗盘，-short 时只抽取其中的一部分
+func TestGaussMatrix_Differential(t *testing.T) {
+	stride := 1
+	if testing.Short() {
+		stride = 97
+	}
+	solver, err := ng.NewSolver("linear", ng.SolverOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 屏蔽 GaussMatrix panic 前打印的矩阵
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+	stdout := os.Stdout
+	os.Stdout = devNull
+	defer func() { os.Stdout = stdout }()
+
+	ringGroups := []ng.RingGroup{ng.Outer, ng.Middle, ng.Inner, ng.OuterMiddle, ng.OuterInner, ng.MiddleInner}
+	speeds := []int{-4, -3, -2, -1, 1, 2, 3, 4}
+	total, solvable, missed, wrong, panicked := 0, 0, 0, 0, 0
+	var firstMissed string
+	n := 0
+	for a := 0; a < len(ringGroups); a++ {
+		for b := a + 1; b < len(ringGroups); b++ {
+			for c := b + 1; c < len(ringGroups); c++ {
+				for location := 0; location < MOD*MOD*MOD; location++ {
+					for speed := 0; speed < len(speeds)*len(speeds)*len(speeds); speed++ {
+						n++
+						if n%stride != 0 {
+							continue
+						}
+						compass := ng.Compass{
+							Rings: []ng.Ring{
+								ng.InnerRing:  {Location: location % MOD, Speed: speeds[speed%8]},
+								ng.MiddleRing: {Location: location / MOD % MOD, Speed: speeds[speed/8%8]},
+								ng.OuterRing:  {Location: location / MOD / MOD, Speed: speeds[speed/64]},
+							},
+							RingGroups: []ng.RingGroup{ringGroups[a], ringGroups[b], ringGroups[c]},
+						}
+						total++
+
+						_, err := solver.Solve(context.Background(), compass)
+						if err != nil && !errors.Is(err, ng.ErrNoSolution) {
+							t.Fatalf("%s: %v", compass.String(), err)
+						}
+						ok := err == nil
+						if ok {
+							solvable++
+						}
+
+						result, err := guess(compass.ToMatrix())
+						if err != nil {
+							panicked++
+						}
+						// GaussMatrix 写回列顺序时会覆盖尚未读取的值，因此可能给出错误的方案，这里只统计而不中断
+						correct, incorrect := false, false
+						for _, counts := range result {
+							steps, err := ng.StepsFromCounts(compass, counts)
+							if err != nil {
+								t.Fatal(err)
+							}
+							if solved, err := ng.CheckSolution(compass, steps); err == nil && solved {
+								correct = true
+							} else {
+								incorrect = true
+							}
+						}
+						if incorrect {
+							wrong++
+						}
+						if ok && !correct {
+							if missed == 0 {
+								firstMissed = compass.String()
+							}
+							missed++
+						}
+					}
+				}
+			}
+		}
+	}
+	t.Logf("%d compasses, %d solvable, GaussMatrix missed %d of them (%d wrong answers and %d panics in total), first missed: %s", total, solvable, missed, wrong, panicked, firstMissed)
+}
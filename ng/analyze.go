@@ -0,0 +1,263 @@
+package ng
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Report 罗盘的可解性分析报告
+type Report struct {
+	Compass  Compass
+	Solvable bool
+	// 一个周期内（每个方案转动 0 到总刻度值减 1 次）不同解法的数量，不考虑 Compass.Constraints，无解时为 0
+	Solutions int
+	// 方案向量组成的矩阵在整数上的秩
+	Rank int
+	// 所有转动能够产生的各圈偏移量构成的群同构于 Z_{f0} ⊕ Z_{f1} ⊕ ...，省略其中为 1 的因子
+	Factors []int
+	// 能够产生的偏移量数量，即 Factors 的乘积
+	Reachable int
+	// 各圈偏移量的全部组合数量，即总刻度值的圈数次方
+	Positions int
+	// 每次转动都保持不变的量
+	Invariants []Invariant
+	// 初始位置与目标位置不一致的不变量，也就是罗盘无解的原因
+	Violations []Invariant
+	// 不变量都一致，但是没有遵守 Compass.Constraints 的转动序列，这也是罗盘无解的原因
+	Constrained bool
+}
+
+// Invariant 每次转动都保持不变的量：Σ Coefficients[i] · 第 i 圈的位置 (mod Modulus)
+type Invariant struct {
+	Coefficients []int // 各圈的系数，下标与 Compass.Rings 一致
+	Modulus      int
+	Value        int // 初始位置下的值
+	Target       int // 目标位置下的值
+}
+
+// Violated 判断初始位置与目标位置下的值是否不一致，不一致时任何转动都无法完成罗盘
+func (inv Invariant) Violated() bool {
+	return inv.Value != inv.Target
+}
+
+// Format 转为文字表述，例如 inner + 2·middle (mod 3)，rings 是罗盘的圈数
+func (inv Invariant) Format(rings int) string {
+	terms := make([]string, 0, len(inv.Coefficients))
+	for i, c := range inv.Coefficients {
+		switch {
+		case c == 0:
+			continue
+		case c == 1:
+			terms = append(terms, RingName(i, rings))
+		default:
+			terms = append(terms, fmt.Sprintf("%d·%s", c, RingName(i, rings)))
+		}
+	}
+	return fmt.Sprintf("%s (mod %d)", strings.Join(terms, " + "), inv.Modulus)
+}
+
+// Analyze 分析罗盘是否有解
+// 将罗盘转换为模总刻度值的线性同余方程组，借助史密斯标准型 U · A · V = D 求出方案向量生成的偏移量的结构：
+// U 的每一行给出一个每次转动都保持不变的量，模数为对应的对角线元素与总刻度值的最大公约数，
+// 罗盘有解当且仅当所有不变量在初始位置与目标位置下的值一致；
+// 罗盘声明了 Constraints 时，还需要通过 ShortestPresses 找到遵守限制的转动序列
+func Analyze(compass Compass) (Report, error) {
+	report := Report{Compass: compass}
+	if err := compass.Validate(); err != nil {
+		return report, fmt.Errorf(`invalid compass, error: %w`, err)
+	}
+
+	m := compass.ScaleCount()
+	ringGroups := uniqueRingGroups(compass.RingGroups)
+	a, _ := compassEquations(compass, ringGroups)
+	f := newSmithForm(a)
+	rows, cols := len(a), len(ringGroups)
+
+	// 对角线元素，超出对角线的部分视为 0
+	d := func(i int) int {
+		if i < rows && i < cols {
+			return f.D[i][i]
+		}
+		return 0
+	}
+
+	report.Reachable, report.Positions = 1, 1
+	for i := 0; i < rows; i++ {
+		report.Positions *= m
+		if d(i) != 0 {
+			report.Rank++
+		}
+
+		g := GCD(d(i), m)
+		if g < m {
+			report.Factors = append(report.Factors, m/g)
+			report.Reachable *= m / g
+		}
+		if g == 1 {
+			continue
+		}
+
+		// 第 i 行满足 (U · A)[i] = d · V⁻¹[i] ≡ 0 (mod g)，因此 U[i] 与各圈位置的内积在转动时保持不变
+		inv := newInvariant(f.U[i], g, compass)
+		if inv.Modulus > 1 {
+			report.Invariants = append(report.Invariants, inv)
+			if inv.Violated() {
+				report.Violations = append(report.Violations, inv)
+			}
+		}
+	}
+
+	report.Solvable = len(report.Violations) == 0
+	if report.Solvable && !compass.Constraints.IsZero() {
+		// 限制与转动的顺序有关，不变量无法反映，需要搜索遵守限制的转动序列
+		_, _, err := ShortestPresses(context.Background(), compass)
+		if errors.Is(err, ErrNoSolution) {
+			report.Solvable, report.Constrained = false, true
+		} else if err != nil {
+			return report, err
+		}
+	}
+	if report.Solvable {
+		// 每个 d · y ≡ c (mod m) 有 GCD(d, m) 个解，V 是幺模矩阵，不改变解的数量
+		report.Solutions = 1
+		for j := 0; j < cols; j++ {
+			report.Solutions *= GCD(d(j), m)
+		}
+	}
+	return report, nil
+}
+
+// newInvariant 由 U 的一行创建模 g 的不变量
+// 系数约简到 [0, g)，第一个非零系数可逆时将其化为 1；系数全部为 0 时模数为 1
+func newInvariant(row []int, g int, compass Compass) Invariant {
+	coefficients := make([]int, len(row))
+	for k, c := range row {
+		coefficients[k] = Mod(c, g)
+	}
+	for _, c := range coefficients {
+		if c == 0 {
+			continue
+		}
+		if inv := ModInv(c, g); inv > 0 {
+			for k := range coefficients {
+				coefficients[k] = Mod(coefficients[k]*inv, g)
+			}
+		}
+		break
+	}
+
+	inv := Invariant{Coefficients: coefficients, Modulus: g}
+	zero := true
+	for k, c := range coefficients {
+		if c != 0 {
+			zero = false
+		}
+		inv.Value += c * compass.Rings[k].Location
+		inv.Target += c * compass.Rings[k].Target
+	}
+	if zero {
+		inv.Modulus = 1
+	}
+	inv.Value, inv.Target = Mod(inv.Value, g), Mod(inv.Target, g)
+	return inv
+}
+
+// Structure 返回偏移量构成的群的文字表述，例如 Z6 ⊕ Z6 ⊕ Z3
+func (r Report) Structure() string {
+	if len(r.Factors) == 0 {
+		return "0"
+	}
+	parts := make([]string, len(r.Factors))
+	for i, factor := range r.Factors {
+		parts[i] = fmt.Sprintf("Z%d", factor)
+	}
+	return strings.Join(parts, " ⊕ ")
+}
+
+// Reasons 返回罗盘无解的原因，每个被违反的不变量一行，没有遵守限制的转动序列时另起一行
+func (r Report) Reasons() []string {
+	rings := len(r.Compass.Rings)
+	reasons := make([]string, 0, len(r.Violations)+1)
+	for _, inv := range r.Violations {
+		reasons = append(reasons, fmt.Sprintf("every press keeps %s unchanged: it is %d now but must be %d at the targets", inv.Format(rings), inv.Value, inv.Target))
+	}
+	if r.Constrained {
+		reasons = append(reasons, "no sequence of presses reaches the targets within the constraints")
+	}
+	return reasons
+}
+
+// String 转为逐行的文字表述，例如:
+//
+//	solvable: 1 solution per period
+//	moves reach 216 of 216 ring offsets (rank 3, Z6 ⊕ Z6 ⊕ Z6)
+func (r Report) String() string {
+	lines := make([]string, 0, 3+len(r.Violations))
+	switch {
+	case !r.Solvable:
+		lines = append(lines, "not solvable")
+	case r.Solutions == 1:
+		lines = append(lines, "solvable: 1 solution per period")
+	default:
+		lines = append(lines, fmt.Sprintf("solvable: %d solutions per period", r.Solutions))
+	}
+	lines = append(lines, fmt.Sprintf("moves reach %d of %d ring offsets (rank %d, %s)", r.Reachable, r.Positions, r.Rank, r.Structure()))
+	lines = append(lines, r.Reasons()...)
+	return strings.Join(lines, "\n")
+}
+
+// reportJSON Report 的 JSON 表述
+type reportJSON struct {
+	Compass    string          `json:"compass"`
+	Solvable   bool            `json:"solvable"`
+	Solutions  int             `json:"solutions"`
+	Rank       int             `json:"rank"`
+	Structure  string          `json:"structure"`
+	Reachable  int             `json:"reachable"`
+	Positions  int             `json:"positions"`
+	Invariants []invariantJSON `json:"invariants"`
+	Reasons    []string        `json:"reasons"`
+	// 参见 Report.Constrained
+	Constrained bool `json:"constrained,omitempty"`
+}
+
+// invariantJSON Invariant 的 JSON 表述
+type invariantJSON struct {
+	Expression   string `json:"expression"`
+	Coefficients []int  `json:"coefficients"`
+	Modulus      int    `json:"modulus"`
+	Value        int    `json:"value"`
+	Target       int    `json:"target"`
+	Violated     bool   `json:"violated"`
+}
+
+// MarshalJSON 实现 json.Marshaler 接口
+func (r Report) MarshalJSON() ([]byte, error) {
+	rings := len(r.Compass.Rings)
+	content := reportJSON{
+		Compass:     r.Compass.String(),
+		Solvable:    r.Solvable,
+		Solutions:   r.Solutions,
+		Rank:        r.Rank,
+		Structure:   r.Structure(),
+		Reachable:   r.Reachable,
+		Positions:   r.Positions,
+		Invariants:  make([]invariantJSON, len(r.Invariants)),
+		Reasons:     r.Reasons(),
+		Constrained: r.Constrained,
+	}
+	for i, inv := range r.Invariants {
+		content.Invariants[i] = invariantJSON{
+			Expression:   inv.Format(rings),
+			Coefficients: inv.Coefficients,
+			Modulus:      inv.Modulus,
+			Value:        inv.Value,
+			Target:       inv.Target,
+			Violated:     inv.Violated(),
+		}
+	}
+	return json.Marshal(content)
+}
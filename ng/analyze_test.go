@@ -0,0 +1,113 @@
+package ng
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func ExampleAnalyze() {
+	// 外圈每次转动 3 个刻度，从刻度 1 出发永远无法回到刻度 0
+	compass, err := ParseCompass("1+3,0+3,0+3/o,m,i")
+	if err != nil {
+		panic(err)
+	}
+	report, err := Analyze(compass)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(report.String())
+	// Output:
+	// not solvable
+	// moves reach 8 of 216 ring offsets (rank 3, Z2 ⊕ Z2 ⊕ Z2)
+	// every press keeps outer (mod 3) unchanged: it is 1 now but must be 0 at the targets
+}
+
+func TestAnalyze(t *testing.T) {
+	linear, err := NewLinearSolver(SolverOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := func(compass Compass) {
+		report, err := Analyze(compass)
+		if err != nil {
+			t.Fatalf("%s: %v", compass.String(), err)
+		}
+		solutions, err := linear.AllSolutions(context.Background(), compass)
+		if err != nil {
+			t.Fatalf("%s: %v", compass.String(), err)
+		}
+		if report.Solvable != (len(solutions) > 0) || report.Solutions != len(solutions) {
+			t.Fatalf("%s: unexpected report %+v (expected %d solutions)", compass.String(), report, len(solutions))
+		}
+		if report.Solvable == (len(report.Violations) > 0) || len(report.Reasons()) != len(report.Violations) {
+			t.Fatalf("%s: unexpected violations %+v", compass.String(), report.Violations)
+		}
+		for _, inv := range report.Invariants {
+			// 不变量在任何转动下都保持不变
+			for _, rg := range compass.RingGroups {
+				sum := 0
+				for i, move := range compass.Movement(rg) {
+					sum += inv.Coefficients[i] * move
+				}
+				if Mod(sum, inv.Modulus) != 0 {
+					t.Fatalf("%s: %s changes after pressing %s", compass.String(), inv.Format(len(compass.Rings)), rg.Format(len(compass.Rings)))
+				}
+			}
+		}
+	}
+
+	expressions := []string{
+		"0+3,3-3,0+2/mi,om,oi",
+		"1+2,0+2,0+2/om,oi,mi",
+		"0+2,3-3,0+3/m+2i-1,om",
+		"0+3>3,3-3,0+2>2/mi,mo,io",
+		"5+1>2,6-2,7+3>7,4+5/ab,dc,ad,b@8",
+		"1+2,0+3,0+1/mi",
+	}
+	for _, expression := range expressions {
+		compass, err := ParseCompass(expression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(compass)
+	}
+	forEachCompass([]RingGroup{Outer, OuterMiddle, MiddleInner}, 7, check)
+	forEachCompass([]RingGroup{OuterMiddle, OuterInner, MiddleInner}, 7, check)
+}
+
+func TestAnalyze_Invalid(t *testing.T) {
+	if _, err := Analyze(Compass{}); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestAnalyze_Constraints(t *testing.T) {
+	compass, err := ParseCompass("0+3,3-3,0+2/mi,om,oi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		constraints Constraints
+		solvable    bool
+	}{
+		// 最优解法 mi3 需要转动 3 次
+		{Constraints{MaxPresses: 3}, true},
+		{Constraints{MaxPresses: 2}, false},
+		{Constraints{MaxGroupPresses: map[RingGroup]int{MiddleInner: 1}}, true},
+		{Constraints{MaxGroupPresses: map[RingGroup]int{MiddleInner: 0, OuterInner: 0}}, false},
+	}
+	for _, test := range tests {
+		compass.Constraints = test.constraints
+		report, err := Analyze(compass)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Solvable != test.solvable || report.Constrained == test.solvable || len(report.Violations) != 0 {
+			t.Errorf("%+v: unexpected report %+v", test.constraints, report)
+		}
+		if !test.solvable && len(report.Reasons()) != 1 {
+			t.Errorf("%+v: unexpected reasons %v", test.constraints, report.Reasons())
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package ng
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+)
+
+func init() {
+	RegisterSolver("bfs", NewBFSSolver, SolverInfo{
+		Description: "breadth-first search over ring positions for the fewest presses",
+		Complete:    true,
+		Optimal:     false,
+		Generalized: true,
+		Ordered:     true,
+	})
+}
+
+// MaxStates 状态空间搜索最多遍历的状态数量，状态总数为总刻度值的圈数次方
+const MaxStates = 1 << 20
+
+// ErrTooManyStates 罗盘的状态总数超过 MaxStates
+var ErrTooManyStates = errors.New(`too many compass states`)
+
+// NewBFSSolver 创建状态空间搜索求解器
+// 以各圈的位置作为状态，从初始状态出发每次转动一个方案，广度优先地搜索到达目标位置的最短转动序列，参见 ShortestPresses。
+// 求出的解法保持转动的顺序，只保证转动总次数最少，不考虑代价函数，并且遵守 Compass.Constraints；
+// 状态只记录位置，无法区分到达同一状态的不同转动次数组合，因此 AllSolutions 交给线性代数求解器完成，
+// 只有 AllSolutions 会对带有限制的罗盘返回 ErrUnsupportedConstraints，Solve 仍然遵守限制
+func NewBFSSolver(opts SolverOptions) (Solver, error) {
+	linear, err := NewLinearSolver(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &bfsSolver{logger: opts.Logger, linear: linear}, nil
+}
+
+// bfsSolver 状态空间搜索求解器的实现
+type bfsSolver struct {
+	logger logr.Logger
+	linear Solver
+}
+
+var _ Solver = &bfsSolver{}
+
+// Solve 求解引航罗盘，返回转动次数最少的按顺序转动的解法
+func (s *bfsSolver) Solve(ctx context.Context, compass Compass) (Steps, error) {
+	presses, distances, err := ShortestPresses(ctx, compass)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.V(1).Info(fmt.Sprintf(`reached %d of %d states`, distances.Reachable(), distances.States()))
+	return pressSteps(presses), nil
+}
+
+// AllSolutions 求出一个周期内全部不同的标准化解法
+func (s *bfsSolver) AllSolutions(ctx context.Context, compass Compass) ([]Steps, error) {
+	return s.linear.AllSolutions(ctx, compass)
+}
+
+// ShortestPresses 从罗盘的初始位置出发，广度优先地遍历全部状态，
+// 返回到达目标位置的最短转动序列（每个元素是依次转动一次的方案）以及到达每个状态的最少转动次数。
+// 目标位置的判断与 CheckSolution 一致；罗盘无解时返回 ErrNoSolution，此时距离表仍然有效。
+// 罗盘声明了 Constraints 时只沿着遵守限制的转动搜索：不再扩展转动次数达到上限的状态，跳过禁止经过的状态，
+// 限制了转动次数的方案会把已经转动的次数一并记入状态，距离表仍然按各圈的位置记录。
+// 状态总数超过 MaxStates 时返回 ErrTooManyStates；每遍历一批状态都会检查 ctx，
+// 被取消或超时时返回携带遍历进度的 *SearchError
+func ShortestPresses(ctx context.Context, compass Compass) ([]RingGroup, *DistanceMap, error) {
+	if err := compass.Validate(); err != nil {
+		return nil, nil, fmt.Errorf(`invalid compass, error: %w`, err)
+	}
+
+	scales := compass.ScaleCount()
+	positions := 1
+	for range compass.Rings {
+		if positions > MaxStates/scales {
+			return nil, nil, fmt.Errorf(`%w: %d^%d exceeds %d`, ErrTooManyStates, scales, len(compass.Rings), MaxStates)
+		}
+		positions *= scales
+	}
+
+	constraints := compass.Constraints
+	ringGroups := uniqueRingGroups(compass.RingGroups)
+	movements := make([][]int, len(ringGroups))
+	limits := make([]int, len(ringGroups))  // 各方案转动次数的上限，-1 表示不限制
+	weights := make([]int, len(ringGroups)) // 各方案已经转动的次数在状态编号中的权重，以位置的数量为单位
+	counters := 1
+	for i, rg := range ringGroups {
+		movements[i] = compass.Movement(rg)
+		limits[i] = -1
+		limit, ok := constraints.MaxGroupPresses[rg]
+		if !ok {
+			continue
+		}
+		if constraints.MaxPresses > 0 {
+			limit = min(limit, constraints.MaxPresses)
+		}
+		if counters > MaxStates/positions/(limit+1) {
+			return nil, nil, fmt.Errorf(`%w: %d positions with the press counts of limited ring groups exceed %d`, ErrTooManyStates, positions, MaxStates)
+		}
+		limits[i], weights[i] = limit, counters
+		counters *= limit + 1
+	}
+	states := positions * counters
+
+	m := newDistanceMap(len(compass.Rings), scales, positions)
+	depths := make([]int, states) // 到达状态所需的转动次数，尚未到达的状态为 -1
+	for i := range depths {
+		depths[i] = -1
+	}
+	parents := make([]int, states)
+	presses := make([]int, states) // 从父状态到达该状态时转动的方案下标
+
+	locations := make([]int, len(compass.Rings))
+	for i, ring := range compass.Rings {
+		locations[i] = Mod(ring.Location, scales)
+	}
+	start := m.encode(locations)
+	m.distances[start] = 0
+	depths[start] = 0
+	queue := append(make([]int, 0, positions), start)
+	next := make([]int, len(compass.Rings))
+	goal := -1
+	for head := 0; head < len(queue); head++ {
+		if head%1024 == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, m, &SearchError{Progress: SearchProgress{Tried: head, Total: states}, Err: err}
+			}
+		}
+
+		// 状态编号的低位是各圈的位置，高位是限制了转动次数的方案已经转动的次数
+		state := queue[head]
+		position, counter := state%positions, state/positions
+		m.decode(position, locations)
+		if goal < 0 && isSolved(compass, locations) {
+			goal = state
+		}
+		if constraints.MaxPresses > 0 && depths[state] >= constraints.MaxPresses {
+			continue
+		}
+		for i, movement := range movements {
+			nextCounter := counter
+			if limits[i] >= 0 {
+				if counter/weights[i]%(limits[i]+1) == limits[i] {
+					continue
+				}
+				nextCounter += weights[i]
+			}
+			for r, move := range movement {
+				next[r] = Mod(locations[r]+move, scales)
+			}
+			if constraints.forbids(next) {
+				continue
+			}
+			nextPosition := m.encode(next)
+			n := nextCounter*positions + nextPosition
+			if depths[n] >= 0 {
+				continue
+			}
+			depths[n] = depths[state] + 1
+			parents[n] = state
+			presses[n] = i
+			if m.distances[nextPosition] < 0 {
+				m.distances[nextPosition] = depths[n]
+			}
+			queue = append(queue, n)
+		}
+	}
+	if goal < 0 {
+		return nil, m, ErrNoSolution
+	}
+
+	// 沿着父状态回到初始状态，得到逆序的转动序列
+	sequence := make([]RingGroup, depths[goal])
+	for state, i := goal, len(sequence)-1; state != start; state, i = parents[state], i-1 {
+		sequence[i] = ringGroups[presses[state]]
+	}
+	return sequence, m, nil
+}
+
+// pressSteps 将按顺序转动的方案合并为保持顺序的解法，连续转动同一个方案记为一步
+func pressSteps(presses []RingGroup) Steps {
+	var steps Steps
+	for _, rg := range presses {
+		if len(steps) > 0 && steps[len(steps)-1].RingGroup == rg {
+			steps[len(steps)-1].Count++
+			continue
+		}
+		steps = append(steps, Step{RingGroup: rg, Count: 1})
+	}
+	return steps
+}
+
+// DistanceMap 从罗盘的初始位置出发，到达每个状态所需的最少转动次数
+// 状态是各圈的位置，下标与 Compass.Rings 一致；罗盘带有限制时只计入遵守限制的转动
+type DistanceMap struct {
+	rings     int
+	scales    int
+	distances []int // 按 encode 的结果编号，无法到达的状态为 -1
+}
+
+// newDistanceMap 创建所有状态都无法到达的距离表
+func newDistanceMap(rings, scales, states int) *DistanceMap {
+	m := &DistanceMap{rings: rings, scales: scales, distances: make([]int, states)}
+	for i := range m.distances {
+		m.distances[i] = -1
+	}
+	return m
+}
+
+// encode 将各圈的位置编号为状态，内圈为最低位
+func (m *DistanceMap) encode(locations []int) int {
+	state := 0
+	for i := len(locations) - 1; i >= 0; i-- {
+		state = state*m.scales + locations[i]
+	}
+	return state
+}
+
+// decode 将状态还原为各圈的位置，写入 locations
+func (m *DistanceMap) decode(state int, locations []int) {
+	for i := range locations {
+		locations[i] = state % m.scales
+		state /= m.scales
+	}
+}
+
+// States 返回状态总数
+func (m *DistanceMap) States() int {
+	return len(m.distances)
+}
+
+// Distance 返回到达 locations 所需的最少转动次数，位置按总刻度值取模，无法到达时返回 false
+func (m *DistanceMap) Distance(locations []int) (int, bool) {
+	if len(locations) != m.rings {
+		return 0, false
+	}
+	normalized := make([]int, len(locations))
+	for i, location := range locations {
+		normalized[i] = Mod(location, m.scales)
+	}
+	distance := m.distances[m.encode(normalized)]
+	return distance, distance >= 0
+}
+
+// Reachable 返回能够到达的状态数量
+func (m *DistanceMap) Reachable() int {
+	reachable := 0
+	for _, distance := range m.distances {
+		if distance >= 0 {
+			reachable++
+		}
+	}
+	return reachable
+}
+
+// Levels 返回与初始位置距离为 0、1、2 ... 的状态数量，最后一个元素对应最远的状态
+func (m *DistanceMap) Levels() []int {
+	var levels []int
+	for _, distance := range m.distances {
+		if distance < 0 {
+			continue
+		}
+		for len(levels) <= distance {
+			levels = append(levels, 0)
+		}
+		levels[distance]++
+	}
+	return levels
+}
+
+// Each 按状态编号依次访问每个能够到达的状态，locations 在两次调用之间会被复用
+func (m *DistanceMap) Each(fn func(locations []int, distance int)) {
+	locations := make([]int, m.rings)
+	for state, distance := range m.distances {
+		if distance < 0 {
+			continue
+		}
+		m.decode(state, locations)
+		fn(locations, distance)
+	}
+}
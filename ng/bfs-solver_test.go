@@ -0,0 +1,110 @@
+package ng
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func ExampleShortestPresses() {
+	compass, err := ParseCompass("0+3,3-3,0+2/mi,om,oi")
+	if err != nil {
+		panic(err)
+	}
+	presses, distances, err := ShortestPresses(context.Background(), compass)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(presses)
+	fmt.Println(distances.Reachable(), distances.States(), distances.Levels())
+	// Output:
+	// [MiddleInner MiddleInner MiddleInner]
+	// 12 216 [1 3 2 2 2 2]
+}
+
+// TestShortestPresses 与线性代数求解器对比：最短转动序列的长度等于最优解法的转动总次数，
+// 能够到达的状态数量等于 Analyze 给出的数量
+func TestShortestPresses(t *testing.T) {
+	stride := 7
+	if testing.Short() {
+		stride = 97
+	}
+	linear, err := NewLinearSolver(SolverOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ringGroups := range [][]RingGroup{
+		{MiddleInner, OuterMiddle, OuterInner},
+		{Outer, MiddleInner, OuterInner},
+		{Inner, Middle, OuterMiddle},
+	} {
+		forEachCompass(ringGroups, stride, func(compass Compass) {
+			presses, distances, err := ShortestPresses(context.Background(), compass)
+			expected, expectedErr := linear.Solve(context.Background(), compass)
+			if !errors.Is(err, expectedErr) {
+				t.Fatalf("%s: unexpected error: %v (expected: %v)", compass.String(), err, expectedErr)
+			}
+			report, analyzeErr := Analyze(compass)
+			if analyzeErr != nil {
+				t.Fatal(analyzeErr)
+			}
+			if distances.Reachable() != report.Reachable {
+				t.Fatalf("%s: reached %d states, expected %d", compass.String(), distances.Reachable(), report.Reachable)
+			}
+			if err != nil {
+				return
+			}
+
+			steps := pressSteps(presses)
+			if ok, err := CheckSolution(compass, steps); err != nil || !ok {
+				t.Fatalf("%s: %s does not pass check: %v", compass.String(), steps.Format(3), err)
+			}
+			if len(presses) != expected.Total() {
+				t.Fatalf("%s: %d presses, expected %d (%s)", compass.String(), len(presses), expected.Total(), expected.String())
+			}
+			targets := make([]int, len(compass.Rings))
+			for i, ring := range compass.Rings {
+				targets[i] = ring.Target
+			}
+			if distance, ok := distances.Distance(targets); !ok || distance != len(presses) {
+				t.Fatalf("%s: unexpected distance %d, %v", compass.String(), distance, ok)
+			}
+		})
+	}
+}
+
+func TestPressSteps(t *testing.T) {
+	// 连续转动同一个方案才会合并，保持转动的顺序
+	steps := pressSteps([]RingGroup{MiddleInner, MiddleInner, OuterMiddle, MiddleInner})
+	expected := Steps{{RingGroup: MiddleInner, Count: 2}, {RingGroup: OuterMiddle, Count: 1}, {RingGroup: MiddleInner, Count: 1}}
+	if !reflect.DeepEqual(steps, expected) {
+		t.Fatalf("unexpected steps %v", steps)
+	}
+	if steps := pressSteps(nil); steps != nil {
+		t.Fatalf("unexpected steps %v", steps)
+	}
+}
+
+func TestShortestPresses_Errors(t *testing.T) {
+	compass, err := ParseCompass("0+1,0+1,0+1,0+1,0+1,0+1,0+1,0+1/a,b@12")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ShortestPresses(context.Background(), compass); !errors.Is(err, ErrTooManyStates) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	compass, err = ParseCompass("0+1,3-2,1+3,7+5/ab,dc,ad,b@8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = ShortestPresses(ctx, compass)
+	var searchErr *SearchError
+	if !errors.As(err, &searchErr) || !errors.Is(err, context.Canceled) || searchErr.Progress.Total != 4096 {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
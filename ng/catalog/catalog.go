@@ -0,0 +1,130 @@
+// Package catalog 内置的引航罗盘谜题目录
+//
+// 目录保存在 catalog.json 中并通过 embed 打包进程序，每个谜题记录 ng.ParseCompass 格式的罗盘信息表达式以及验证过的解法。
+//
+// 注意：内置目录还不是游戏内引航罗盘谜题的完整目录，目前只收录了本仓库 README 中记录的两个谜题。
+// 游戏内谜题所在的地区与地图位置没有可查证的数据来源，因此目录不记录这两项，也不提供按地区查找。
+package catalog
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/AyakuraYuki/go-starrail-compass/ng"
+)
+
+//go:embed catalog.json
+var catalogJSON []byte
+
+// Entry 目录中的一个谜题
+type Entry struct {
+	ID         string `json:"id"`
+	Expression string `json:"expression"` // 罗盘信息表达式，格式参见 ng.ParseCompass
+	Solution   string `json:"solution"`   // 验证过的解法，格式参见 ng.ParseSteps
+	Note       string `json:"note,omitempty"`
+}
+
+// Compass 解析谜题的罗盘信息表达式
+func (e Entry) Compass() (ng.Compass, error) {
+	compass, err := ng.ParseCompass(e.Expression)
+	if err != nil {
+		return compass, err
+	}
+	if err := compass.Validate(); err != nil {
+		return compass, fmt.Errorf("invalid compass: %w", err)
+	}
+	return compass, nil
+}
+
+// Steps 解析谜题的解法
+func (e Entry) Steps() (ng.Steps, error) {
+	compass, err := e.Compass()
+	if err != nil {
+		return nil, err
+	}
+	return ng.ParseSteps(e.Solution, len(compass.Rings))
+}
+
+// Catalog 谜题目录
+type Catalog struct {
+	entries []Entry
+	byID    map[string]int
+}
+
+// Load 解析 JSON 格式的谜题目录，并检查每个谜题的表达式与解法
+func Load(data []byte) (*Catalog, error) {
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("decode catalog error: %w", err)
+	}
+
+	c := &Catalog{entries: entries, byID: make(map[string]int, len(entries))}
+	for i, entry := range entries {
+		if entry.ID == "" {
+			return nil, fmt.Errorf("entry %d has no id", i)
+		}
+		if _, dup := c.byID[entry.ID]; dup {
+			return nil, fmt.Errorf("duplicate entry id %q", entry.ID)
+		}
+		c.byID[entry.ID] = i
+
+		if err := entry.verify(); err != nil {
+			return nil, fmt.Errorf("entry %q: %w", entry.ID, err)
+		}
+	}
+	return c, nil
+}
+
+// verify 检查谜题的解法能否完成罗盘
+func (e Entry) verify() error {
+	compass, err := e.Compass()
+	if err != nil {
+		return err
+	}
+	steps, err := e.Steps()
+	if err != nil {
+		return err
+	}
+	solved, err := ng.CheckSolution(compass, steps)
+	if err != nil {
+		return err
+	}
+	if !solved {
+		return errors.New("solution does not solve the compass")
+	}
+	return nil
+}
+
+var (
+	defaultOnce    sync.Once
+	defaultCatalog *Catalog
+)
+
+// Default 返回内置的谜题目录
+func Default() *Catalog {
+	defaultOnce.Do(func() {
+		c, err := Load(catalogJSON)
+		if err != nil {
+			panic("catalog: invalid embedded catalog: " + err.Error())
+		}
+		defaultCatalog = c
+	})
+	return defaultCatalog
+}
+
+// All 返回全部谜题，按目录中的顺序排列
+func (c *Catalog) All() []Entry {
+	return append([]Entry(nil), c.entries...)
+}
+
+// Lookup 按 ID 查找谜题
+func (c *Catalog) Lookup(id string) (Entry, bool) {
+	i, ok := c.byID[id]
+	if !ok {
+		return Entry{}, false
+	}
+	return c.entries[i], true
+}
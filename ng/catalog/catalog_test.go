@@ -0,0 +1,117 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/AyakuraYuki/go-starrail-compass/ng"
+)
+
+func ExampleCatalog_Lookup() {
+	entry, ok := Default().Lookup("readme-defect")
+	if !ok {
+		panic("not found")
+	}
+	fmt.Println(entry.Expression, entry.Solution)
+	// Output:
+	// 0+3,3-3,0+2/mi,om,oi mi3
+}
+
+// TestDefault_Solvers 检查内置目录中的每个谜题都能被所有已注册的求解器求解
+func TestDefault_Solvers(t *testing.T) {
+	entries := Default().All()
+	if len(entries) == 0 {
+		t.Fatal("expected entries in the embedded catalog")
+	}
+	for _, info := range ng.Solvers() {
+		solver, err := ng.NewSolver(info.Name, ng.SolverOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, entry := range entries {
+			compass, err := entry.Compass()
+			if err != nil {
+				t.Fatalf("%s: %v", entry.ID, err)
+			}
+			solution, err := solver.Solve(context.Background(), compass)
+			if err != nil {
+				t.Fatalf("%s/%s: %v", info.Name, entry.ID, err)
+			}
+			if ok, err := ng.CheckSolution(compass, solution); err != nil || !ok {
+				t.Fatalf("%s/%s: solution %s does not pass check: %v", info.Name, entry.ID, solution.String(), err)
+			}
+
+			// 最优求解器给出的解法不会比目录中记录的解法转动更多次
+			steps, err := entry.Steps()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Optimal && solution.Total() > steps.Total() {
+				t.Errorf("%s/%s: solution %s is longer than %s", info.Name, entry.ID, solution.String(), entry.Solution)
+			}
+		}
+	}
+}
+
+// TestDefault_Rate 检查内置目录中游戏内谜题的难度评级
+func TestDefault_Rate(t *testing.T) {
+	expected := map[string]ng.Difficulty{
+		// 转动 4 次方案 mi，另有两种同样转动 4 次的解法
+		"readme-matrix": {Level: ng.Easy, Score: 4, Presses: 4, OptimalSolutions: 3, Groups: 1, Greedy: true},
+		// 转动 3 次方案 mi，另有一种同样转动 3 次的解法
+		"readme-defect": {Level: ng.Easy, Score: 3, Presses: 3, OptimalSolutions: 2, Groups: 1, Greedy: true},
+	}
+	entries := Default().All()
+	if len(entries) != len(expected) {
+		t.Fatalf("expected ratings for %d entries, got %d entries", len(expected), len(entries))
+	}
+	for _, entry := range entries {
+		compass, err := entry.Compass()
+		if err != nil {
+			t.Fatalf("%s: %v", entry.ID, err)
+		}
+		difficulty, err := ng.Rate(compass)
+		if err != nil {
+			t.Fatalf("%s: %v", entry.ID, err)
+		}
+		if difficulty != expected[entry.ID] {
+			t.Errorf("%s: unexpected difficulty %+v (expected: %+v)", entry.ID, difficulty, expected[entry.ID])
+		}
+	}
+}
+
+func TestCatalog_Lookup(t *testing.T) {
+	c, err := Load([]byte(`[
+		{"id": "a", "expression": "0+3,3-3,0+2/mi,om,oi", "solution": "mi3"},
+		{"id": "b", "expression": "0+3,0-3,4-1/mi,om,oi", "solution": "mi4"},
+		{"id": "c", "expression": "0+2,3-3,0+3/mi,om,oi", "solution": "om3"},
+		{"id": "d", "expression": "0+3,3-3>3,0+2/mi,mo,io", "solution": ""}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if entry, ok := c.Lookup("b"); !ok || entry.Solution != "mi4" {
+		t.Fatalf("unexpected entry: %+v, %v", entry, ok)
+	}
+	if _, ok := c.Lookup("z"); ok {
+		t.Fatal("expected no entry")
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []string{
+		`{`,
+		`[{"expression": "0+3,3-3,0+2/mi,om,oi", "solution": "mi3"}]`,
+		`[{"id": "a", "expression": "0+3,3-3,0+2/mi,om,oi", "solution": "mi3"}, {"id": "a", "expression": "0+3,3-3,0+2/mi,om,oi", "solution": "mi3"}]`,
+		`[{"id": "a", "expression": "hello", "solution": "mi3"}]`,
+		`[{"id": "a", "expression": "0+3,3-3,0+2/mi,om,oi", "solution": "mi2"}]`,
+		`[{"id": "a", "expression": "0+3,3-3,0+2/mi,om,oi", "solution": "o1"}]`,
+	}
+	for _, data := range tests {
+		if _, err := Load([]byte(data)); err == nil {
+			t.Errorf("expected an error for %s", data)
+		}
+	}
+}
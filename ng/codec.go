@@ -0,0 +1,433 @@
+package ng
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 本文件实现罗盘相关类型的文本与 JSON 编解码
+// 文本编码同时会被 YAML 等支持 encoding.TextMarshaler 的编码库使用
+
+var (
+	_ encoding.TextMarshaler   = RingGroup(0)
+	_ encoding.TextUnmarshaler = (*RingGroup)(nil)
+	_ json.Marshaler           = RingGroup(0)
+	_ json.Unmarshaler         = (*RingGroup)(nil)
+	_ encoding.TextMarshaler   = Ring{}
+	_ encoding.TextUnmarshaler = (*Ring)(nil)
+	_ json.Marshaler           = Ring{}
+	_ json.Unmarshaler         = (*Ring)(nil)
+	_ encoding.TextMarshaler   = Compass{}
+	_ encoding.TextUnmarshaler = (*Compass)(nil)
+	_ json.Marshaler           = Compass{}
+	_ json.Unmarshaler         = (*Compass)(nil)
+	_ encoding.TextMarshaler   = Step{}
+	_ encoding.TextUnmarshaler = (*Step)(nil)
+	_ json.Marshaler           = Step{}
+	_ json.Unmarshaler         = (*Step)(nil)
+	_ encoding.TextMarshaler   = Steps{}
+	_ encoding.TextUnmarshaler = (*Steps)(nil)
+	_ json.Marshaler           = Steps{}
+	_ json.Unmarshaler         = (*Steps)(nil)
+)
+
+// MarshalText 实现 encoding.TextMarshaler 接口
+// 经典三圈罗盘的方案编码为缩写，例如 om；单独编码时无法得知罗盘的圈数，
+// 包含第四个及以上的圈的方案编码为二进制，例如 0b1001；
+// 作为 Compass 的一部分编码时，方案使用与罗盘信息表达式相同的字母缩写，例如 ad
+func (rg RingGroup) MarshalText() ([]byte, error) {
+	if rg == 0 {
+		return nil, errors.New("invalid ring group")
+	}
+	if name := rg.ShortName(); name != "" {
+		return []byte(name), nil
+	}
+	return []byte("0b" + strconv.FormatUint(uint64(rg), 2)), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler 接口
+// 支持经典三圈罗盘中的缩写（例如 om 或 mo）与名称（例如 OuterMiddle），以及二进制表述（例如 0b1001）
+func (rg *RingGroup) UnmarshalText(text []byte) error {
+	expression := string(text)
+	if strings.HasPrefix(expression, "0b") {
+		value, err := strconv.ParseUint(expression[2:], 2, 8)
+		if err != nil || value == 0 {
+			return fmt.Errorf(`invalid ring group: "%s"`, expression)
+		}
+		*rg = RingGroup(value)
+		return nil
+	}
+	for _, v := range []RingGroup{Outer, Middle, Inner, OuterMiddle, OuterInner, MiddleInner} {
+		if v.Name() == expression {
+			*rg = v
+			return nil
+		}
+	}
+	parsed, speeds, err := parseRingGroup(expression, 3)
+	if err != nil {
+		return err
+	}
+	if speeds != nil {
+		return fmt.Errorf(`unexpected speeds in ring group: "%s"`, expression)
+	}
+	*rg = parsed
+	return nil
+}
+
+// MarshalJSON 实现 json.Marshaler 接口，编码为与 MarshalText 相同的字符串
+func (rg RingGroup) MarshalJSON() ([]byte, error) {
+	text, err := rg.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler 接口
+func (rg *RingGroup) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf(`ring group must be a string: %w`, err)
+	}
+	return rg.UnmarshalText([]byte(text))
+}
+
+// MarshalText 实现 encoding.TextMarshaler 接口，编码为罗盘圈表达式，例如 0+2 或 0+2>3
+func (r Ring) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler 接口
+func (r *Ring) UnmarshalText(text []byte) error {
+	ring, err := parseRing(string(text))
+	if err != nil {
+		return err
+	}
+	*r = ring
+	return nil
+}
+
+// ringJSON Ring 的 JSON 表述
+type ringJSON struct {
+	Location int `json:"location"`
+	Speed    int `json:"speed"`
+	Target   int `json:"target,omitempty"`
+}
+
+// MarshalJSON 实现 json.Marshaler 接口，编码为对象
+func (r Ring) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ringJSON(r))
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler 接口，支持对象以及罗盘圈表达式字符串
+func (r *Ring) UnmarshalJSON(data []byte) error {
+	if isJSONString(data) {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		return r.UnmarshalText([]byte(text))
+	}
+	var content ringJSON
+	if err := json.Unmarshal(data, &content); err != nil {
+		return err
+	}
+	*r = Ring(content)
+	return nil
+}
+
+// MarshalText 实现 encoding.TextMarshaler 接口，编码为标准化的罗盘信息表达式
+func (c Compass) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler 接口，格式参见 ParseCompass
+func (c *Compass) UnmarshalText(text []byte) error {
+	compass, err := ParseCompass(string(text))
+	if err != nil {
+		return err
+	}
+	*c = compass
+	return nil
+}
+
+// compassJSON Compass 的 JSON 表述
+type compassJSON struct {
+	Rings       []Ring           `json:"rings"`
+	Scales      int              `json:"scales,omitempty"`
+	RingGroups  []string         `json:"ringGroups"`
+	Constraints *constraintsJSON `json:"constraints,omitempty"`
+}
+
+// constraintsJSON Compass.Constraints 的 JSON 表述，方案使用与罗盘信息表达式相同的缩写
+// 单独编码 RingGroup 时无法得知罗盘的圈数，因此不能直接编码 Constraints
+type constraintsJSON struct {
+	MaxPresses      int            `json:"maxPresses,omitempty"`
+	MaxGroupPresses map[string]int `json:"maxGroupPresses,omitempty"`
+	Forbidden       [][]int        `json:"forbidden,omitempty"`
+}
+
+// MarshalJSON 实现 json.Marshaler 接口
+// 各圈按 Compass.Rings 的顺序（从内到外）排列，方案使用与罗盘信息表达式相同的缩写，
+// 声明了旋转速度的方案在缩写中附带速度，例如 m+2i-1；方案的顺序保持不变；
+// 罗盘信息表达式无法表述 Constraints，带有限制的罗盘只能通过 JSON 对象完整编码
+func (c Compass) MarshalJSON() ([]byte, error) {
+	content := compassJSON{
+		Rings:      c.Rings,
+		Scales:     c.Scales,
+		RingGroups: make([]string, len(c.RingGroups)),
+	}
+	if content.Rings == nil {
+		content.Rings = []Ring{}
+	}
+	for i, rg := range c.RingGroups {
+		content.RingGroups[i] = c.formatRingGroup(rg)
+	}
+	if !c.Constraints.IsZero() {
+		content.Constraints = &constraintsJSON{MaxPresses: c.Constraints.MaxPresses, Forbidden: c.Constraints.Forbidden}
+		if len(c.Constraints.MaxGroupPresses) > 0 {
+			content.Constraints.MaxGroupPresses = make(map[string]int, len(c.Constraints.MaxGroupPresses))
+			for rg, limit := range c.Constraints.MaxGroupPresses {
+				content.Constraints.MaxGroupPresses[rg.Format(len(c.Rings))] = limit
+			}
+		}
+	}
+	return json.Marshal(content)
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler 接口，支持对象以及罗盘信息表达式字符串
+func (c *Compass) UnmarshalJSON(data []byte) error {
+	if isJSONString(data) {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		return c.UnmarshalText([]byte(text))
+	}
+
+	var content compassJSON
+	if err := json.Unmarshal(data, &content); err != nil {
+		return err
+	}
+	compass := Compass{Rings: content.Rings, Scales: content.Scales}
+	if len(content.RingGroups) > 0 {
+		ringGroups, groupSpeeds, err := parseRingGroups(strings.Join(content.RingGroups, ","), len(content.Rings))
+		if err != nil {
+			return fmt.Errorf("parse ring groups error: %w", err)
+		}
+		compass.RingGroups = ringGroups
+		compass.GroupSpeeds = groupSpeeds
+	}
+	if content.Constraints != nil {
+		compass.Constraints = Constraints{MaxPresses: content.Constraints.MaxPresses, Forbidden: content.Constraints.Forbidden}
+		for name, limit := range content.Constraints.MaxGroupPresses {
+			rg, speeds, err := parseRingGroup(name, len(content.Rings))
+			if err != nil {
+				return fmt.Errorf("parse constraints error: %w", err)
+			}
+			if speeds != nil {
+				return fmt.Errorf(`parse constraints error: unexpected speeds in ring group: "%s"`, name)
+			}
+			if compass.Constraints.MaxGroupPresses == nil {
+				compass.Constraints.MaxGroupPresses = make(map[RingGroup]int)
+			}
+			compass.Constraints.MaxGroupPresses[rg] = limit
+		}
+	}
+	if err := compass.Validate(); err != nil {
+		return fmt.Errorf("invalid compass: %w", err)
+	}
+	if err := checkRanges(compass); err != nil {
+		return fmt.Errorf("invalid compass: %w", err)
+	}
+	*c = compass
+	return nil
+}
+
+// checkRanges 与 ParseCompass 一样检查各圈的位置、旋转速度、目标位置以及方案的旋转速度是否超出总刻度值的范围
+func checkRanges(c Compass) error {
+	scales := c.ScaleCount()
+	for i, ring := range c.Rings {
+		if _, err := (ringNode{Ring: ring}).check(scales); err != nil {
+			return fmt.Errorf(`%s ring: %w`, RingName(i, len(c.Rings)), err)
+		}
+	}
+	for _, rg := range c.RingGroups {
+		for _, speed := range c.GroupSpeeds[rg] {
+			if speed != 0 && Abs(speed) >= scales {
+				return fmt.Errorf(`speed %+d of ring group %s is out of range [1, %d)`, speed, rg.Format(len(c.Rings)), scales)
+			}
+		}
+	}
+	return nil
+}
+
+// MarshalText 实现 encoding.TextMarshaler 接口，编码为方案缩写与转动次数，例如 mi3
+func (s Step) MarshalText() ([]byte, error) {
+	rg, err := s.RingGroup.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(rg, []byte("0b")) {
+		// 二进制表述的方案与转动次数之间使用 x 分隔，例如 0b1001x3
+		return []byte(fmt.Sprintf("%sx%d", rg, s.Count)), nil
+	}
+	return []byte(fmt.Sprintf("%s%d", rg, s.Count)), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler 接口
+func (s *Step) UnmarshalText(text []byte) error {
+	expression := string(text)
+	group, count := "", ""
+	if strings.HasPrefix(expression, "0b") {
+		group, count, _ = strings.Cut(expression, "x")
+	} else if split := strings.IndexFunc(expression, func(r rune) bool { return r >= '0' && r <= '9' }); split > 0 {
+		group, count = expression[:split], expression[split:]
+	}
+	if group == "" || count == "" {
+		return fmt.Errorf(`invalid step: "%s"`, expression)
+	}
+	var rg RingGroup
+	if err := rg.UnmarshalText([]byte(group)); err != nil {
+		return err
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil {
+		return fmt.Errorf(`invalid count in step: "%s"`, expression)
+	}
+	*s = Step{RingGroup: rg, Count: n}
+	return nil
+}
+
+// stepJSON Step 的 JSON 表述
+type stepJSON struct {
+	RingGroup RingGroup `json:"ringGroup"`
+	Count     int       `json:"count"`
+}
+
+// MarshalJSON 实现 json.Marshaler 接口，编码为对象，例如 {"ringGroup":"mi","count":3}
+func (s Step) MarshalJSON() ([]byte, error) {
+	return json.Marshal(stepJSON(s))
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler 接口，支持对象以及 mi3 这样的字符串
+func (s *Step) UnmarshalJSON(data []byte) error {
+	if isJSONString(data) {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		return s.UnmarshalText([]byte(text))
+	}
+	var content stepJSON
+	if err := json.Unmarshal(data, &content); err != nil {
+		return err
+	}
+	*s = Step(content)
+	return nil
+}
+
+// MarshalText 实现 encoding.TextMarshaler 接口
+// 编码为逗号分隔的步骤，例如 om1,mi3，与 String 不同的是会保留步骤的顺序
+func (s Steps) MarshalText() ([]byte, error) {
+	parts := make([]string, len(s))
+	for i, step := range s {
+		text, err := step.MarshalText()
+		if err != nil {
+			return nil, fmt.Errorf("marshal step at index %d error: %w", i, err)
+		}
+		parts[i] = string(text)
+	}
+	return []byte(strings.Join(parts, ",")), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler 接口
+func (s *Steps) UnmarshalText(text []byte) error {
+	steps := make(Steps, 0)
+	if len(text) > 0 {
+		for i, part := range strings.Split(string(text), ",") {
+			var step Step
+			if err := step.UnmarshalText([]byte(part)); err != nil {
+				return fmt.Errorf("unmarshal step at index %d error: %w", i, err)
+			}
+			steps = append(steps, step)
+		}
+	}
+	*s = steps
+	return nil
+}
+
+// MarshalJSON 实现 json.Marshaler 接口，编码为 Step 对象的数组并保留步骤的顺序
+func (s Steps) MarshalJSON() ([]byte, error) {
+	steps := []Step(s)
+	if steps == nil {
+		steps = []Step{}
+	}
+	return json.Marshal(steps)
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler 接口，支持数组以及 om1,mi3 这样的字符串
+func (s *Steps) UnmarshalJSON(data []byte) error {
+	if isJSONString(data) {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		return s.UnmarshalText([]byte(text))
+	}
+	var steps []Step
+	if err := json.Unmarshal(data, &steps); err != nil {
+		return err
+	}
+	if steps == nil {
+		steps = []Step{}
+	}
+	*s = steps
+	return nil
+}
+
+// SolutionJSON 罗盘的一个解法的 JSON 表述，方案使用与罗盘信息表达式相同的缩写，供命令行与 HTTP 服务输出解法
+// 与 Steps 的 JSON 编码不同，方案的缩写与罗盘的圈数有关，四圈及以上的罗盘同样使用字母缩写，例如 ad
+type SolutionJSON struct {
+	Expression string     `json:"expression"`
+	Steps      []StepJSON `json:"steps"`
+	Presses    int        `json:"presses"`
+}
+
+// StepJSON 解法中一步的 JSON 表述，参见 SolutionJSON
+type StepJSON struct {
+	RingGroup string `json:"ringGroup"`
+	Count     int    `json:"count"`
+}
+
+// NewSolutionJSON 返回罗盘的解法 steps 的 JSON 表述
+func NewSolutionJSON(compass Compass, steps Steps) SolutionJSON {
+	return SolutionJSON{
+		Expression: steps.Format(len(compass.Rings)),
+		Steps:      NewStepsJSON(compass, steps),
+		Presses:    steps.Total(),
+	}
+}
+
+// NewStepsJSON 返回罗盘的解谜步骤的 JSON 表述，保留步骤的顺序，省略转动次数不大于 0 的步骤
+func NewStepsJSON(compass Compass, steps Steps) []StepJSON {
+	result := make([]StepJSON, 0, len(steps))
+	for _, step := range steps {
+		if step.Count <= 0 {
+			continue
+		}
+		result = append(result, StepJSON{RingGroup: step.RingGroup.Format(len(compass.Rings)), Count: step.Count})
+	}
+	return result
+}
+
+// isJSONString 判断 JSON 数据是否为字符串
+func isJSONString(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && data[0] == '"'
+}
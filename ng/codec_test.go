@@ -0,0 +1,236 @@
+package ng
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func ExampleCompass_MarshalJSON() {
+	compass, err := ParseCompass("0+2,3-3,0+3>3/mi,om,oi")
+	if err != nil {
+		panic(err)
+	}
+	data, err := json.Marshal(compass)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(data))
+	// Output:
+	// {"rings":[{"location":0,"speed":3,"target":3},{"location":3,"speed":-3},{"location":0,"speed":2}],"ringGroups":["mi","om","oi"]}
+}
+
+func ExampleSteps_MarshalJSON() {
+	data, err := json.Marshal(Steps{{RingGroup: OuterMiddle, Count: 1}, {RingGroup: MiddleInner, Count: 3}})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(data))
+	// Output:
+	// [{"ringGroup":"om","count":1},{"ringGroup":"mi","count":3}]
+}
+
+func TestRingGroup_UnmarshalText(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected RingGroup
+	}{
+		{"om", OuterMiddle},
+		{"mo", OuterMiddle},
+		{"OuterMiddle", OuterMiddle},
+		{"i", Inner},
+		{"0b1001", 0b1001},
+	}
+	for _, test := range tests {
+		var rg RingGroup
+		if err := rg.UnmarshalText([]byte(test.text)); err != nil {
+			t.Fatalf("unmarshal %q error: %v", test.text, err)
+		}
+		if rg != test.expected {
+			t.Fatalf("unexpected ring group of %q: %b (expected: %b)", test.text, rg, test.expected)
+		}
+	}
+
+	for _, text := range []string{"", "x", "oo", "m+2", "0b", "0b0", "0b123"} {
+		var rg RingGroup
+		if err := rg.UnmarshalText([]byte(text)); err == nil {
+			t.Fatalf("expected an error for %q, got %b", text, rg)
+		}
+	}
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	values := []any{
+		RingGroup(OuterInner),
+		RingGroup(0b1001),
+		Ring{Location: 3, Speed: -3},
+		Ring{Location: 0, Speed: 2, Target: 3},
+		Step{RingGroup: MiddleInner, Count: 3},
+		Step{RingGroup: 0b1100, Count: 1},
+		Steps{{RingGroup: OuterMiddle, Count: 1}, {RingGroup: MiddleInner, Count: 3}},
+		Steps{},
+	}
+	for _, value := range values {
+		for _, codec := range []string{"text", "json"} {
+			var data []byte
+			var err error
+			if codec == "text" {
+				data, err = value.(interface{ MarshalText() ([]byte, error) }).MarshalText()
+			} else {
+				data, err = json.Marshal(value)
+			}
+			if err != nil {
+				t.Fatalf("marshal %v as %s error: %v", value, codec, err)
+			}
+
+			decoded := reflect.New(reflect.TypeOf(value))
+			if codec == "text" {
+				err = decoded.Interface().(interface{ UnmarshalText([]byte) error }).UnmarshalText(data)
+			} else {
+				err = json.Unmarshal(data, decoded.Interface())
+			}
+			if err != nil {
+				t.Fatalf("unmarshal %s %s error: %v", codec, data, err)
+			}
+			if !reflect.DeepEqual(decoded.Elem().Interface(), value) {
+				t.Fatalf("unexpected %s round trip of %s: %v (expected: %v)", codec, data, decoded.Elem().Interface(), value)
+			}
+		}
+	}
+}
+
+func TestCompass_RoundTrip(t *testing.T) {
+	expressions := []string{
+		"0+2,3-3,0+3/mi,om,oi",
+		"0+2,3-3,0+3>3/mi,om,oi",
+		"0+2,3-3,0+3/m+2i-1,om",
+		"5+1>2,6-2,7+3>7,4+5/ab,dc,ad,b@8",
+	}
+	for _, expression := range expressions {
+		compass, err := ParseCompass(expression)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := json.Marshal(compass)
+		if err != nil {
+			t.Fatalf("marshal %s error: %v", expression, err)
+		}
+		var decoded Compass
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("unmarshal %s error: %v", data, err)
+		}
+		if decoded.String() != compass.String() || !reflect.DeepEqual(decoded.RingGroups, compass.RingGroups) {
+			t.Fatalf("unexpected json round trip of %s: %s", expression, decoded.String())
+		}
+
+		text, err := compass.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := decoded.UnmarshalText(text); err != nil {
+			t.Fatalf("unmarshal %s error: %v", text, err)
+		}
+		if decoded.String() != compass.String() {
+			t.Fatalf("unexpected text round trip of %s: %s", expression, decoded.String())
+		}
+	}
+}
+
+func TestCompass_RoundTrip_Constraints(t *testing.T) {
+	compass, err := ParseCompass("5+1>2,6-2,7+3>7,4+5/ab,dc,ad,b@8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	compass.Constraints = Constraints{
+		MaxPresses:      8,
+		MaxGroupPresses: map[RingGroup]int{compass.RingGroups[0]: 2, compass.RingGroups[3]: 0},
+		Forbidden:       [][]int{{AnyLocation, 3, AnyLocation, 0}},
+	}
+	data, err := json.Marshal(compass)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Compass
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal %s error: %v", data, err)
+	}
+	if !reflect.DeepEqual(decoded.Constraints, compass.Constraints) {
+		t.Fatalf("unexpected constraints: %+v", decoded.Constraints)
+	}
+	// 四圈罗盘的方案使用字母缩写
+	if !bytes.Contains(data, []byte(`"maxGroupPresses":{"ab":2,"b":0}`)) {
+		t.Fatalf("unexpected constraints in %s", data)
+	}
+
+	// 没有限制时不输出 constraints
+	compass.Constraints = Constraints{}
+	data, err = json.Marshal(compass)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("constraints")) {
+		t.Fatalf("unexpected constraints in %s", data)
+	}
+}
+
+func TestCodec_UnmarshalJSON_Strings(t *testing.T) {
+	var content struct {
+		Compass Compass `json:"compass"`
+		Ring    Ring    `json:"ring"`
+		Steps   Steps   `json:"steps"`
+	}
+	data := `{"compass":"0+2,3-3,0+3/mi,om,oi","ring":"0+2>3","steps":"om1,mi3"}`
+	if err := json.Unmarshal([]byte(data), &content); err != nil {
+		t.Fatal(err)
+	}
+	if content.Compass.String() != "0+2,3-3,0+3/mi,oi,om" {
+		t.Fatalf("unexpected compass: %s", content.Compass.String())
+	}
+	if content.Ring != (Ring{Location: 0, Speed: 2, Target: 3}) {
+		t.Fatalf("unexpected ring: %+v", content.Ring)
+	}
+	if expected := (Steps{{RingGroup: OuterMiddle, Count: 1}, {RingGroup: MiddleInner, Count: 3}}); !reflect.DeepEqual(content.Steps, expected) {
+		t.Fatalf("unexpected steps: %v", content.Steps)
+	}
+}
+
+func TestCompass_UnmarshalJSON_Invalid(t *testing.T) {
+	tests := []string{
+		`{"rings":[],"ringGroups":["mi"]}`,
+		`{"rings":[{"location":0,"speed":0},{"location":0,"speed":1},{"location":0,"speed":1}],"ringGroups":["mi"]}`,
+		`{"rings":[{"location":0,"speed":1},{"location":0,"speed":1},{"location":0,"speed":1}],"ringGroups":["mx"]}`,
+		`"hello"`,
+		`{"rings":[{"location":0,"speed":1},{"location":0,"speed":1},{"location":0,"speed":1}],"ringGroups":["mi"],"constraints":{"maxGroupPresses":{"om":1}}}`,
+		// 与 ParseCompass 一样检查位置、旋转速度与目标位置的范围
+		`{"rings":[{"location":6,"speed":1},{"location":0,"speed":1},{"location":0,"speed":1}],"ringGroups":["mi"]}`,
+		`{"rings":[{"location":0,"speed":7},{"location":0,"speed":1},{"location":0,"speed":1}],"ringGroups":["mi"]}`,
+		`{"rings":[{"location":0,"speed":1,"target":-1},{"location":0,"speed":1},{"location":0,"speed":1}],"ringGroups":["mi"]}`,
+		`{"rings":[{"location":0,"speed":1},{"location":0,"speed":1},{"location":0,"speed":1}],"ringGroups":["m+6i"]}`,
+		`{"rings":[{"location":0,"speed":1},{"location":0,"speed":1},{"location":0,"speed":1}],"ringGroups":["mi"],"constraints":{"maxGroupPresses":{"0b11":1}}}`,
+	}
+	for _, data := range tests {
+		var compass Compass
+		if err := json.Unmarshal([]byte(data), &compass); err == nil {
+			t.Fatalf("expected an error for %s", data)
+		}
+	}
+}
+
+func TestNewSolutionJSON(t *testing.T) {
+	compass, err := ParseCompass("5+1>2,6-2,7+3>7,4+5/ab,dc,ad,b@8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps := Steps{{RingGroup: compass.RingGroups[2], Count: 3}, {RingGroup: compass.RingGroups[0], Count: 0}, {RingGroup: compass.RingGroups[3], Count: 1}}
+	data, err := json.Marshal(NewSolutionJSON(compass, steps))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 四圈罗盘的方案同样使用字母缩写；expression 是标准化的表达式，steps 省略转动次数为 0 的步骤并保留顺序
+	if expected := `{"expression":"b1,ad3","steps":[{"ringGroup":"ad","count":3},{"ringGroup":"b","count":1}],"presses":4}`; string(data) != expected {
+		t.Fatalf("unexpected json %s (expected: %s)", data, expected)
+	}
+}
@@ -7,34 +7,51 @@ import (
 	"strings"
 )
 
-// SCALES 总刻度值
+// SCALES 默认的总刻度值，游戏内的引航罗盘都被分成 6 个刻度
 const SCALES = 6
 
+// MaxRings 罗盘最多支持的圈数，受限于 RingGroup 的位数
+const MaxRings = 8
+
+// 经典三圈罗盘中各圈在 Compass.Rings 中的下标
+const (
+	InnerRing  = 0
+	MiddleRing = 1
+	OuterRing  = 2
+)
+
 // Ring 定义引航罗盘中的一圈
 type Ring struct {
 	// 位置
 	// 指针从罗盘正左方∠0°沿顺时针方向旋转至当前位置所需的刻度
-	// 刻度以∠60°为一度
+	// 经典罗盘的刻度以∠60°为一度
 	// 例如：0 表示正左方∠0°位置，3 表示正右方∠180°位置
-	// 有效范围是 0-6
+	// 有效范围是 0 到总刻度值减 1
 	Location int
 	// 旋转速度
 	// 单位为刻度，符号表示旋转方向，正数是顺时针，负数是逆时针
 	// 旋转速度不会是 0（目前游戏内没有发现不旋转的圈）
 	// 例如：-1 表示每次逆时针旋转 1 个刻度；2 表示每次顺时针旋转 2 个刻度
-	// 有效范围是 1-4
+	// 经典罗盘的有效范围是 1-4
 	Speed int
+	// 目标位置
+	// 解谜完成时指针需要停留的刻度，默认为 0，即正左方∠0°位置
+	Target int
 }
 
 // String 转为字符串表述
 func (r *Ring) String() string {
+	if r.Target != 0 {
+		return fmt.Sprintf("%d%+d>%d", r.Location, r.Speed, r.Target)
+	}
 	return fmt.Sprintf("%d%+d", r.Location, r.Speed)
 }
 
 // RingGroup 引航罗盘方案分组
+// 第 i 位表示方案是否包含 Compass.Rings[i]，即最低位是最内侧的圈
 type RingGroup uint8
 
-// RingGroup 的合法值
+// 经典三圈罗盘中 RingGroup 的合法值
 const (
 	Outer       RingGroup = 0b100
 	Middle      RingGroup = 0b010
@@ -44,7 +61,7 @@ const (
 	MiddleInner           = Middle | Inner
 )
 
-// Name 返回方案名称
+// Name 返回经典三圈罗盘中的方案名称
 func (rg RingGroup) Name() string {
 	switch rg {
 	case Outer:
@@ -63,7 +80,7 @@ func (rg RingGroup) Name() string {
 	return ""
 }
 
-// ShortName 返回缩写
+// ShortName 返回经典三圈罗盘中的方案缩写
 func (rg RingGroup) ShortName() string {
 	switch rg {
 	case Outer:
@@ -82,23 +99,120 @@ func (rg RingGroup) ShortName() string {
 	return ""
 }
 
+// Format 返回方案在 rings 个圈的罗盘中的缩写
+// 三圈罗盘使用 o、m、i 表示外圈、中圈、内圈，
+// 其他罗盘从最外侧的圈开始依次使用 a、b、c ... 表示，缩写中的圈同样从外到内排列
+func (rg RingGroup) Format(rings int) string {
+	// 方案包含超出罗盘的圈时，按方案实际涉及的圈数表述，避免丢失信息
+	for rings < MaxRings && rg>>rings > 0 {
+		rings++
+	}
+	letters := ringLetters(rings)
+	var sb strings.Builder
+	for i := rings - 1; i >= 0; i-- {
+		if rg&(1<<i) > 0 {
+			sb.WriteByte(letters[i])
+		}
+	}
+	return sb.String()
+}
+
+// Contains 判断方案是否包含下标为 ring 的圈
+func (rg RingGroup) Contains(ring int) bool {
+	return rg&(1<<ring) > 0
+}
+
 // String 转为字符串表述
 func (rg RingGroup) String() string {
-	return rg.Name()
+	if name := rg.Name(); name != "" {
+		return name
+	}
+	return rg.Format(0)
+}
+
+// ringLetters 返回 rings 个圈的罗盘中各圈的缩写，下标与 Compass.Rings 一致
+func ringLetters(rings int) string {
+	if rings == 3 {
+		return "imo"
+	}
+	letters := make([]byte, rings)
+	for i := range letters {
+		letters[i] = byte('a' + rings - 1 - i)
+	}
+	return string(letters)
+}
+
+// RingName 返回 rings 个圈的罗盘中下标为 index 的圈的名称，用于文字描述与错误信息
+// 经典三圈罗盘使用 outer、middle、inner，其他罗盘使用方案缩写中的字母，例如 a
+func RingName(index, rings int) string {
+	if rings == 3 {
+		return [...]string{"inner", "middle", "outer"}[index]
+	}
+	return string(ringLetters(rings)[index])
 }
 
 // Compass 引航罗盘
 type Compass struct {
-	OuterRing  Ring        // 外圈
-	MiddleRing Ring        // 中圈
-	InnerRing  Ring        // 内圈
+	Rings      []Ring      // 各圈，从内到外排列，经典三圈罗盘可以使用 InnerRing、MiddleRing、OuterRing 作为下标
+	Scales     int         // 总刻度值，为 0 时使用默认值 SCALES
 	RingGroups []RingGroup // 方案，可以同时旋转的一个或多个圈组成的一个分组
+	// 方案各自的旋转速度，可选
+	// 下标与 Rings 一致，值为 0 的圈使用 Ring.Speed，
+	// 例如某个方案转动中圈 2 个刻度，而另一个方案转动中圈 -1 个刻度
+	GroupSpeeds map[RingGroup][]int
+	// 解谜时需要遵守的限制，可选，参见 Constraints
+	Constraints Constraints
+}
+
+// NewCompass 创建经典的三圈罗盘
+func NewCompass(outer, middle, inner Ring, ringGroups ...RingGroup) Compass {
+	return Compass{
+		Rings:      []Ring{inner, middle, outer},
+		RingGroups: ringGroups,
+	}
+}
+
+// ScaleCount 返回罗盘的总刻度值
+func (c *Compass) ScaleCount() int {
+	if c.Scales == 0 {
+		return SCALES
+	}
+	return c.Scales
 }
 
 // Validate 合法化
 func (c *Compass) Validate() error {
-	if c.OuterRing.Speed == 0 || c.MiddleRing.Speed == 0 || c.InnerRing.Speed == 0 {
-		return errors.New("ring speed must be declared")
+	if c.ScaleCount() < 2 {
+		return fmt.Errorf("scales must be at least 2, got %d", c.ScaleCount())
+	}
+	if len(c.Rings) == 0 || len(c.Rings) > MaxRings {
+		return fmt.Errorf("ring count must be between 1 and %d, got %d", MaxRings, len(c.Rings))
+	}
+	for _, ring := range c.Rings {
+		if ring.Speed == 0 {
+			return errors.New("ring speed must be declared")
+		}
+	}
+	for _, rg := range c.RingGroups {
+		if rg == 0 || rg>>len(c.Rings) > 0 {
+			return fmt.Errorf("ring group %s is out of range for a compass with %d rings", rg.Format(len(c.Rings)), len(c.Rings))
+		}
+	}
+	for rg, speeds := range c.GroupSpeeds {
+		if !c.IsRingGroupSupported(rg) {
+			return fmt.Errorf("group speeds declared for an unsupported ring group: %s", rg.Format(len(c.Rings)))
+		}
+		if len(speeds) != len(c.Rings) {
+			return fmt.Errorf("group speeds of %s must have %d elements, got %d", rg.Format(len(c.Rings)), len(c.Rings), len(speeds))
+		}
+		for i, speed := range speeds {
+			if speed != 0 && !rg.Contains(i) {
+				return fmt.Errorf("group speeds of %s declares a ring which is not in the group", rg.Format(len(c.Rings)))
+			}
+		}
+	}
+	if err := c.Constraints.validate(c); err != nil {
+		return fmt.Errorf("invalid constraints: %w", err)
 	}
 	return nil
 }
@@ -116,8 +230,27 @@ func (c *Compass) IsRingGroupSupported(ringGroup RingGroup) bool {
 	return false
 }
 
+// Movement 返回转动一次指定方案时各圈转动的刻度，下标与 Compass.Rings 一致
+// 方案声明了自己的旋转速度时优先使用，否则使用各圈的旋转速度
+func (c *Compass) Movement(ringGroup RingGroup) []int {
+	speeds := c.GroupSpeeds[ringGroup]
+	movement := make([]int, len(c.Rings))
+	for i, ring := range c.Rings {
+		if !ringGroup.Contains(i) {
+			continue
+		}
+		movement[i] = ring.Speed
+		if i < len(speeds) && speeds[i] != 0 {
+			movement[i] = speeds[i]
+		}
+	}
+	return movement
+}
+
 // Standardize 标准化罗盘
 func (c *Compass) Standardize() *Compass {
+	scales := c.ScaleCount()
+
 	// 拷贝原始罗盘的方案，并排序
 	sortedRingGroups := make([]RingGroup, len(c.RingGroups))
 	copy(sortedRingGroups, c.RingGroups)
@@ -134,38 +267,83 @@ func (c *Compass) Standardize() *Compass {
 		deduplicatedRingGroups = append(deduplicatedRingGroups, v)
 	}
 
+	rings := make([]Ring, len(c.Rings))
+	for i, ring := range c.Rings {
+		rings[i] = Ring{
+			Location: Mod(ring.Location, scales),
+			Speed:    ring.Speed % scales,
+			Target:   Mod(ring.Target, scales),
+		}
+	}
+
+	// 拷贝方案的旋转速度，去掉与圈的旋转速度相同的声明
+	var groupSpeeds map[RingGroup][]int
+	for rg, speeds := range c.GroupSpeeds {
+		standardized := make([]int, len(rings))
+		declared := false
+		for i := range standardized {
+			// 按模比较，例如 6 个刻度时速度 5 与 -1 是同一种转动
+			if i < len(speeds) && Mod(speeds[i], scales) != 0 && Mod(speeds[i], scales) != Mod(rings[i].Speed, scales) {
+				standardized[i] = speeds[i] % scales
+				declared = true
+			}
+		}
+		if !declared {
+			continue
+		}
+		if groupSpeeds == nil {
+			groupSpeeds = make(map[RingGroup][]int)
+		}
+		groupSpeeds[rg] = standardized
+	}
+
 	return &Compass{
-		OuterRing: Ring{
-			Location: Mod(c.OuterRing.Location, SCALES),
-			Speed:    c.OuterRing.Speed % SCALES,
-		},
-		MiddleRing: Ring{
-			Location: Mod(c.MiddleRing.Location, SCALES),
-			Speed:    c.MiddleRing.Speed % SCALES,
-		},
-		InnerRing: Ring{
-			Location: Mod(c.InnerRing.Location, SCALES),
-			Speed:    c.InnerRing.Speed % SCALES,
-		},
-		RingGroups: deduplicatedRingGroups,
+		Rings:       rings,
+		Scales:      scales,
+		RingGroups:  deduplicatedRingGroups,
+		GroupSpeeds: groupSpeeds,
+		Constraints: c.Constraints,
 	}
 }
 
+// formatRingGroup 返回方案的缩写，方案声明了旋转速度时在对应的圈后追加速度
+func (c *Compass) formatRingGroup(ringGroup RingGroup) string {
+	speeds, ok := c.GroupSpeeds[ringGroup]
+	if !ok {
+		return ringGroup.Format(len(c.Rings))
+	}
+	letters := ringLetters(len(c.Rings))
+	var sb strings.Builder
+	for i := len(c.Rings) - 1; i >= 0; i-- {
+		if !ringGroup.Contains(i) {
+			continue
+		}
+		sb.WriteByte(letters[i])
+		if i < len(speeds) && speeds[i] != 0 {
+			sb.WriteString(fmt.Sprintf("%+d", speeds[i]))
+		}
+	}
+	return sb.String()
+}
+
 // String 转为字符串表述
 func (c *Compass) String() string {
 	// 标准化罗盘
 	std := c.Standardize()
+	// 各圈从外到内排列
+	rings := make([]string, len(std.Rings))
+	for i := range std.Rings {
+		rings[len(rings)-1-i] = std.Rings[i].String()
+	}
 	// 转换方案获取简称
 	ringGroups := make([]string, len(std.RingGroups))
 	for i := range ringGroups {
-		ringGroups[i] = std.RingGroups[i].ShortName()
-	}
-	content := strings.Join(ringGroups, ",")
-	// 组合罗盘信息
-	return fmt.Sprintf("%s,%s,%s/%s",
-		std.OuterRing.String(),
-		std.MiddleRing.String(),
-		std.InnerRing.String(),
-		content,
-	)
+		ringGroups[i] = std.formatRingGroup(std.RingGroups[i])
+	}
+	// 组合罗盘信息，非默认刻度值时追加刻度
+	content := fmt.Sprintf("%s/%s", strings.Join(rings, ","), strings.Join(ringGroups, ","))
+	if std.Scales != SCALES {
+		content += fmt.Sprintf("@%d", std.Scales)
+	}
+	return content
 }
@@ -17,9 +17,11 @@ func ExampleRing_String() {
 
 func ExampleCompass_String() {
 	compass := &Compass{
-		InnerRing:  Ring{Location: 0, Speed: 1},
-		MiddleRing: Ring{Location: 4, Speed: -4},
-		OuterRing:  Ring{Location: 0, Speed: 2},
+		Rings: []Ring{
+			InnerRing:  {Location: 0, Speed: 1},
+			MiddleRing: {Location: 4, Speed: -4},
+			OuterRing:  {Location: 0, Speed: 2},
+		},
 		RingGroups: []RingGroup{
 			OuterInner,
 			OuterMiddle,
@@ -37,14 +39,97 @@ func TestParseCompass(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	expectedCompass := Compass{
-		OuterRing:  Ring{Location: 0, Speed: 2},
-		MiddleRing: Ring{Location: 3, Speed: -3},
-		InnerRing:  Ring{Location: 0, Speed: 3},
-		RingGroups: []RingGroup{MiddleInner, OuterMiddle, OuterInner},
-	}
+	expectedCompass := NewCompass(
+		Ring{Location: 0, Speed: 2},
+		Ring{Location: 3, Speed: -3},
+		Ring{Location: 0, Speed: 3},
+		MiddleInner, OuterMiddle, OuterInner,
+	)
 
 	if compass.String() != expectedCompass.String() {
 		t.Fatalf("unexpected result: %#v (expected: %#v)", compass.String(), expectedCompass.String())
 	}
 }
+
+func ExampleRingGroup_Format() {
+	fmt.Println(OuterMiddle.Format(3))
+	fmt.Println(RingGroup(0b1001).Format(4))
+	fmt.Println(RingGroup(0b00110).Format(5))
+	// Output:
+	// om
+	// ad
+	// cd
+}
+
+func ExampleRingName() {
+	fmt.Println(RingName(OuterRing, 3))
+	fmt.Println(RingName(0, 4))
+	fmt.Println(RingName(3, 4))
+	// Output:
+	// outer
+	// d
+	// a
+}
+
+func TestParseCompass_Generalized(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   string
+		rings      int
+		scales     int
+	}{
+		{"0+1,3-2,1+3,7+5/ab,dc,ad,b@8", "0+1,3-2,1+3,7+5/cd,b,ad,ab@8", 4, 8},
+		{"11+5,0-1,6+7,2+2,9-11/abcde,e,bd@12", "11+5,0-1,6+7,2+2,9-11/e,bd,abcde@12", 5, 12},
+		{"3+1,2-1/a,b,ab", "3+1,2-1/b,a,ab", 2, SCALES},
+		{"0+2,3-3,0+3/mi,om,oi@6", "0+2,3-3,0+3/mi,oi,om", 3, SCALES},
+		{"0+2>3,3-3>0,0+3>5/mi,om,oi", "0+2>3,3-3,0+3>5/mi,oi,om", 3, SCALES},
+		{"5+1>2,6-2,7+3>7,4+5/ab,dc@8", "5+1>2,6-2,7+3>7,4+5/cd,ab@8", 4, 8},
+		{"0+2,3-3,0+3/m+2i-1,om,oi", "0+2,3-3,0+3/m+2i-1,oi,om", 3, SCALES},
+		{"0+2,3-3,0+3/m-3i+3,o-4m,oi", "0+2,3-3,0+3/mi,oi,om", 3, SCALES}, // 外圈速度 -4 与 2 是同一种转动
+		{"0+2,3-3,0+3/m-3i+3,o-3m,oi", "0+2,3-3,0+3/mi,oi,o-3m", 3, SCALES},
+		{"0+2,3-1,0+3/m+5i,om,oi", "0+2,3-1,0+3/mi,oi,om", 3, SCALES}, // 速度 5 与 -1 是同一种转动
+		{"0+2,3-3,0+3/m+3i,om,oi", "0+2,3-3,0+3/mi,oi,om", 3, SCALES}, // 速度 3 与 -3 是同一种转动
+		{"5+1,6-2,7+3,4+5/a+2b,dc-3@8", "5+1,6-2,7+3,4+5/c-3d,a+2b@8", 4, 8},
+	}
+	for _, test := range tests {
+		compass, err := ParseCompass(test.expression)
+		if err != nil {
+			t.Fatalf("%s: %v", test.expression, err)
+		}
+		if err := compass.Validate(); err != nil {
+			t.Fatalf("%s: %v", test.expression, err)
+		}
+		if len(compass.Rings) != test.rings || compass.ScaleCount() != test.scales {
+			t.Errorf("%s: unexpected shape: %d rings, %d scales", test.expression, len(compass.Rings), compass.ScaleCount())
+		}
+		if compass.String() != test.expected {
+			t.Errorf("%s: unexpected result: %s (expected: %s)", test.expression, compass.String(), test.expected)
+		}
+		// 标准化后的表述可以重新解析
+		reparsed, err := ParseCompass(compass.String())
+		if err != nil || reparsed.String() != compass.String() {
+			t.Errorf("%s: round trip failed: %s, %v", test.expression, reparsed.String(), err)
+		}
+	}
+}
+
+func TestParseCompass_Invalid(t *testing.T) {
+	expressions := []string{
+		"6+2,3-3,0+3/mi,om,oi",                  // 位置超出刻度
+		"0+6,3-3,0+3/mi,om,oi",                  // 速度超出刻度
+		"0+2,3-3,0+3/mi,om,oa",                  // 三圈罗盘不使用字母缩写
+		"0+1,3-2,1+3,7+5/ab,ae@8",               // 方案包含不存在的圈
+		"0+1,3-2,1+3,7+5/ab,aa@8",               // 方案包含重复的圈
+		"0+1,3-2,1+3,8+5/ab,cd@8",               // 位置超出自定义刻度
+		"0+1,3-2,1+3,7+5/ab,cd@1",               // 刻度过少
+		"0+1,0+1,0+1,0+1,0+1,0+1,0+1,0+1,0+1/a", // 圈数过多
+		"0+2>6,3-3,0+3/mi,om,oi",                // 目标位置超出刻度
+	}
+	for _, expression := range expressions {
+		if compass, err := ParseCompass(expression); err == nil {
+			t.Errorf("%s: expected error, got %s", expression, compass.String())
+		} else {
+			t.Log(err)
+		}
+	}
+}
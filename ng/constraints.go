@@ -0,0 +1,153 @@
+package ng
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// AnyLocation 用于 Constraints.Forbidden，表示圈可以停在任意位置
+const AnyLocation = -1
+
+// ErrUnsupportedConstraints 求解器不支持带有 Compass.Constraints 的罗盘
+var ErrUnsupportedConstraints = errors.New(`the solver does not support compass constraints`)
+
+// Constraints 解谜时需要遵守的限制，零值表示没有任何限制
+// 限制与转动的顺序有关，只有 SolverInfo.Ordered 的求解器会遵守，
+// 其他求解器遇到带有限制的罗盘时返回 ErrUnsupportedConstraints
+type Constraints struct {
+	// 转动总次数的上限，0 表示不限制
+	MaxPresses int `json:"maxPresses,omitempty"`
+	// 各方案转动次数的上限，没有声明的方案不限制，声明为 0 的方案不能转动
+	MaxGroupPresses map[RingGroup]int `json:"maxGroupPresses,omitempty"`
+	// 转动过程中不能经过的状态，下标与 Compass.Rings 一致，值为 AnyLocation 的圈可以停在任意位置，
+	// 例如三圈罗盘中的 {AnyLocation, AnyLocation, 3} 表示外圈不能停在刻度 3；
+	// 每次转动之后的状态都会被检查，初始状态不受限制
+	Forbidden [][]int `json:"forbidden,omitempty"`
+}
+
+// IsZero 判断是否没有任何限制
+func (c Constraints) IsZero() bool {
+	return c.MaxPresses == 0 && len(c.MaxGroupPresses) == 0 && len(c.Forbidden) == 0
+}
+
+// validate 检查限制是否适用于罗盘
+func (c Constraints) validate(compass *Compass) error {
+	rings := len(compass.Rings)
+	if c.MaxPresses < 0 {
+		return fmt.Errorf("max presses must not be negative, got %d", c.MaxPresses)
+	}
+	for rg, limit := range c.MaxGroupPresses {
+		if !compass.IsRingGroupSupported(rg) {
+			return fmt.Errorf("max presses declared for an unsupported ring group: %s", rg.Format(rings))
+		}
+		if limit < 0 {
+			return fmt.Errorf("max presses of %s must not be negative, got %d", rg.Format(rings), limit)
+		}
+	}
+	scales := compass.ScaleCount()
+	for i, locations := range c.Forbidden {
+		if len(locations) != rings {
+			return fmt.Errorf("forbidden state %d must have %d elements, got %d", i, rings, len(locations))
+		}
+		for _, location := range locations {
+			if location != AnyLocation && (location < 0 || location >= scales) {
+				return fmt.Errorf("forbidden state %d has a location %d out of range [0, %d)", i, location, scales)
+			}
+		}
+	}
+	return nil
+}
+
+// forbids 判断各圈停在 locations 时是否经过了禁止的状态
+func (c Constraints) forbids(locations []int) bool {
+	for _, forbidden := range c.Forbidden {
+		matched := true
+		for i, location := range forbidden {
+			if location != AnyLocation && location != locations[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// check 按顺序逐次转动罗盘，返回第一个违反限制的转动，没有违反限制时返回 nil
+func (c Constraints) check(compass Compass, steps Steps) error {
+	scales := compass.ScaleCount()
+	locations := make([]int, len(compass.Rings))
+	for i, ring := range compass.Rings {
+		locations[i] = Mod(ring.Location, scales)
+	}
+
+	press := 0
+	counts := make(map[RingGroup]int)
+	for i, step := range steps {
+		if step.Count < 0 {
+			return fmt.Errorf(`step %d has a negative count: %d`, i, step.Count)
+		}
+		movement := compass.Movement(step.RingGroup)
+		for n := 0; n < step.Count; n++ {
+			press++
+			counts[step.RingGroup]++
+			violation := &ConstraintViolation{Press: press, RingGroup: step.RingGroup, rings: len(compass.Rings)}
+			if c.MaxPresses > 0 && press > c.MaxPresses {
+				violation.Constraint, violation.Limit = MaxPressesConstraint, c.MaxPresses
+				return violation
+			}
+			if limit, ok := c.MaxGroupPresses[step.RingGroup]; ok && counts[step.RingGroup] > limit {
+				violation.Constraint, violation.Limit = MaxGroupPressesConstraint, limit
+				return violation
+			}
+			for r, move := range movement {
+				locations[r] = Mod(locations[r]+move, scales)
+			}
+			if c.forbids(locations) {
+				violation.Constraint, violation.Locations = ForbiddenConstraint, append([]int(nil), locations...)
+				return violation
+			}
+		}
+	}
+	return nil
+}
+
+// ConstraintKind 限制的种类
+type ConstraintKind string
+
+// 限制的种类，与 Constraints 的 JSON 字段名一致
+const (
+	MaxPressesConstraint      ConstraintKind = "maxPresses"
+	MaxGroupPressesConstraint ConstraintKind = "maxGroupPresses"
+	ForbiddenConstraint       ConstraintKind = "forbidden"
+)
+
+// ConstraintViolation CheckSolution 发现解法违反 Compass.Constraints 时返回的错误
+type ConstraintViolation struct {
+	Constraint ConstraintKind
+	Press      int       // 违反限制的是第几次转动，从 1 开始
+	RingGroup  RingGroup // 这次转动的方案
+	Limit      int       // 违反转动次数上限时的上限
+	Locations  []int     // 经过禁止的状态时各圈的位置，下标与 Compass.Rings 一致
+	rings      int
+}
+
+// Error 实现 error 接口
+func (v *ConstraintViolation) Error() string {
+	rings := v.rings
+	switch v.Constraint {
+	case MaxPressesConstraint:
+		return fmt.Sprintf(`press %d exceeds the limit of %d presses`, v.Press, v.Limit)
+	case MaxGroupPressesConstraint:
+		return fmt.Sprintf(`press %d exceeds the limit of %d presses for ring group %s`, v.Press, v.Limit, v.RingGroup.Format(rings))
+	default:
+		locations := make([]string, rings)
+		for i, location := range v.Locations {
+			locations[rings-1-i] = fmt.Sprintf("%s %d", RingName(i, rings), location)
+		}
+		return fmt.Sprintf(`press %d of ring group %s passes through a forbidden state (%s)`, v.Press, v.RingGroup.Format(rings), strings.Join(locations, ", "))
+	}
+}
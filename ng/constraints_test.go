@@ -0,0 +1,165 @@
+package ng
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func ExampleConstraints() {
+	compass, err := ParseCompass("0+3,3-3,0+2/mi,om,oi")
+	if err != nil {
+		panic(err)
+	}
+	// 方案 mi 最多转动一次，最短的解法 mi3 不再可用
+	compass.Constraints = Constraints{MaxGroupPresses: map[RingGroup]int{MiddleInner: 1}}
+	solver, err := NewSolver("bfs", SolverOptions{})
+	if err != nil {
+		panic(err)
+	}
+	solution, err := solver.Solve(context.Background(), compass)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(solution.Format(3))
+
+	_, err = CheckSolution(compass, Steps{{RingGroup: MiddleInner, Count: 3}})
+	fmt.Println(err)
+	// Output:
+	// mi1,oi2
+	// press 2 exceeds the limit of 1 presses for ring group mi
+}
+
+func TestCheckSolution_Constraints(t *testing.T) {
+	compass, err := ParseCompass("0+3,3-3,0+2/mi,om,oi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		constraints Constraints
+		steps       string
+		constraint  ConstraintKind // 为空时解谜步骤遵守限制
+		press       int
+	}{
+		{Constraints{MaxPresses: 3}, "mi3", "", 0},
+		{Constraints{MaxPresses: 2}, "mi3", MaxPressesConstraint, 3},
+		{Constraints{MaxPresses: 2}, "mi1", "", 0},
+		{Constraints{MaxGroupPresses: map[RingGroup]int{MiddleInner: 2}}, "mi2,om1,mi1", MaxGroupPressesConstraint, 4},
+		{Constraints{MaxGroupPresses: map[RingGroup]int{OuterMiddle: 0}}, "mi3", "", 0},
+		{Constraints{MaxGroupPresses: map[RingGroup]int{OuterMiddle: 0}}, "mi1,om1", MaxGroupPressesConstraint, 2},
+		// 内圈第一次转动后停在 2
+		{Constraints{Forbidden: [][]int{{2, AnyLocation, AnyLocation}}}, "mi3", ForbiddenConstraint, 1},
+		// 初始状态不受限制
+		{Constraints{Forbidden: [][]int{{0, 3, 0}}}, "mi3", "", 0},
+		{Constraints{Forbidden: [][]int{{4, 3, 0}}}, "mi3", ForbiddenConstraint, 2},
+	}
+	for _, test := range tests {
+		compass.Constraints = test.constraints
+		steps, err := ParseSteps(test.steps, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		solved, err := CheckSolution(compass, steps)
+		var violation *ConstraintViolation
+		if test.constraint == "" {
+			if err != nil {
+				t.Errorf("%+v %s: unexpected error: %v", test.constraints, test.steps, err)
+			}
+			continue
+		}
+		if solved || !errors.As(err, &violation) {
+			t.Errorf("%+v %s: expected violation, got %v, %v", test.constraints, test.steps, solved, err)
+			continue
+		}
+		if violation.Constraint != test.constraint || violation.Press != test.press {
+			t.Errorf("%+v %s: unexpected violation: %v", test.constraints, test.steps, violation)
+		}
+	}
+}
+
+func TestConstraints_Validate(t *testing.T) {
+	compass, err := ParseCompass("0+3,3-3,0+2/mi,om,oi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, constraints := range []Constraints{
+		{MaxPresses: -1},
+		{MaxGroupPresses: map[RingGroup]int{Inner: 1}},
+		{MaxGroupPresses: map[RingGroup]int{MiddleInner: -1}},
+		{Forbidden: [][]int{{0, 0}}},
+		{Forbidden: [][]int{{0, 6, 0}}},
+		{Forbidden: [][]int{{0, -2, 0}}},
+	} {
+		compass.Constraints = constraints
+		if err := compass.Validate(); err == nil {
+			t.Errorf("%+v: expected error", constraints)
+		}
+	}
+}
+
+// TestShortestPresses_Constraints 求出的转动序列遵守限制，并且转动总次数不少于没有限制时的最短序列；
+// 总次数的上限比最短序列少一次时无解
+func TestShortestPresses_Constraints(t *testing.T) {
+	stride := 13
+	if testing.Short() {
+		stride = 97
+	}
+	ringGroups := []RingGroup{MiddleInner, OuterMiddle, OuterInner}
+	forEachCompass(ringGroups, stride, func(compass Compass) {
+		shortest, _, err := ShortestPresses(context.Background(), compass)
+		if err != nil || len(shortest) == 0 {
+			return
+		}
+
+		for _, constraints := range []Constraints{
+			{MaxGroupPresses: map[RingGroup]int{shortest[0]: 1}},
+			{Forbidden: [][]int{{1, AnyLocation, AnyLocation}, {AnyLocation, 2, AnyLocation}}},
+			{MaxPresses: len(shortest) + 1, MaxGroupPresses: map[RingGroup]int{OuterInner: 2}},
+		} {
+			compass.Constraints = constraints
+			presses, _, err := ShortestPresses(context.Background(), compass)
+			if errors.Is(err, ErrNoSolution) {
+				continue
+			}
+			if err != nil {
+				t.Fatalf("%s %+v: unexpected error: %v", compass.String(), constraints, err)
+			}
+			if len(presses) < len(shortest) {
+				t.Fatalf("%s %+v: %d presses, shorter than %d", compass.String(), constraints, len(presses), len(shortest))
+			}
+			steps := pressSteps(presses)
+			if ok, err := CheckSolution(compass, steps); err != nil || !ok {
+				t.Fatalf("%s %+v: %s does not pass check: %v", compass.String(), constraints, steps.Format(3), err)
+			}
+		}
+
+		compass.Constraints = Constraints{MaxPresses: len(shortest) - 1}
+		if len(shortest) > 1 {
+			if _, _, err := ShortestPresses(context.Background(), compass); !errors.Is(err, ErrNoSolution) {
+				t.Fatalf("%s: unexpected error: %v", compass.String(), err)
+			}
+		}
+	})
+}
+
+func TestSolver_UnsupportedConstraints(t *testing.T) {
+	compass, err := ParseCompass("0+3,3-3,0+2/mi,om,oi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	compass.Constraints = Constraints{MaxPresses: 3}
+	for _, info := range Solvers() {
+		solver, err := NewSolver(info.Name, SolverOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = solver.Solve(context.Background(), compass)
+		if info.Ordered != !errors.Is(err, ErrUnsupportedConstraints) {
+			t.Errorf("%s: unexpected error: %v", info.Name, err)
+		}
+		if _, err := solver.AllSolutions(context.Background(), compass); !errors.Is(err, ErrUnsupportedConstraints) {
+			t.Errorf("%s: unexpected error from AllSolutions: %v", info.Name, err)
+		}
+	}
+}
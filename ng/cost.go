@@ -0,0 +1,115 @@
+package ng
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultCostName 未指定代价函数时使用的代价函数名称，对应 TotalPresses
+const DefaultCostName = "presses"
+
+// ErrUnknownCost 没有该名称的代价函数
+var ErrUnknownCost = errors.New(`unknown cost`)
+
+// costFuncs 可以通过名称选择的内置代价函数，参见 CostFuncByName
+var costFuncs = map[string]CostFunc{
+	"presses":   TotalPresses,
+	"groups":    DistinctGroups,
+	"switches":  GroupSwitches,
+	"animation": AnimationTime,
+}
+
+// CostFuncByName 按名称返回内置的代价函数，供命令行与 HTTP 服务选择，没有该名称的代价函数时返回 ErrUnknownCost
+func CostFuncByName(name string) (CostFunc, error) {
+	cost, ok := costFuncs[name]
+	if !ok {
+		return nil, fmt.Errorf(`%w %q (must be one of %s)`, ErrUnknownCost, name, strings.Join(CostFuncNames(), ", "))
+	}
+	return cost, nil
+}
+
+// CostFuncNames 返回全部内置代价函数的名称，按名称排列
+func CostFuncNames() []string {
+	names := make([]string, 0, len(costFuncs))
+	for name := range costFuncs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CostFunc 解法的代价函数，代价越小的解法越优
+// 求解器会在全部解法中选出代价最小的一个，代价相同时依次比较
+// 转动总次数、使用的方案数量以及字符串表述，保证结果稳定
+type CostFunc func(compass Compass, steps Steps) int
+
+// TotalPresses 转动总次数
+func TotalPresses(_ Compass, steps Steps) int {
+	return steps.Total()
+}
+
+// DistinctGroups 使用到的方案数量
+func DistinctGroups(_ Compass, steps Steps) int {
+	return len(steps.Standardize())
+}
+
+// GroupSwitches 按给定顺序执行解法时切换方案的次数
+// 按原有顺序计算，不做标准化：保持转动顺序的求解器（参见 SolverInfo.Ordered）给出的解法可能多次回到同一个方案，
+// 例如 mi1,oi2,mi1 切换 2 次，而标准化后的 mi2,oi2 只切换 1 次
+func GroupSwitches(_ Compass, steps Steps) int {
+	switches := 0
+	var last RingGroup
+	for _, step := range steps {
+		if step.Count <= 0 {
+			continue
+		}
+		if last != 0 && step.RingGroup != last {
+			switches++
+		}
+		last = step.RingGroup
+	}
+	return switches
+}
+
+// AnimationTime 转动动画的总时长，以刻度为单位
+// 每次转动的时长取决于该方案中转动刻度最多的圈
+func AnimationTime(compass Compass, steps Steps) int {
+	total := 0
+	for _, step := range steps {
+		if step.Count <= 0 {
+			continue
+		}
+		longest := 0
+		for _, move := range compass.Movement(step.RingGroup) {
+			if Abs(move) > longest {
+				longest = Abs(move)
+			}
+		}
+		total += step.Count * longest
+	}
+	return total
+}
+
+// costOrDefault 未指定代价函数时使用转动总次数
+func costOrDefault(cost CostFunc) CostFunc {
+	if cost == nil {
+		return TotalPresses
+	}
+	return cost
+}
+
+// lessSolution 判断解法 a 是否优于解法 b
+func lessSolution(compass Compass, cost CostFunc, a, b Steps) bool {
+	if costA, costB := cost(compass, a), cost(compass, b); costA != costB {
+		return costA < costB
+	}
+	if a.Total() != b.Total() {
+		return a.Total() < b.Total()
+	}
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a.String() < b.String()
+}
@@ -0,0 +1,104 @@
+package ng
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCostFunc(t *testing.T) {
+	compass, err := ParseCompass("0+2,3-3,0+1/mi,om,oi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps := Steps{
+		{RingGroup: MiddleInner, Count: 1},
+		{RingGroup: OuterMiddle, Count: 2},
+		{RingGroup: MiddleInner, Count: 3},
+	}
+
+	tests := []struct {
+		name     string
+		cost     CostFunc
+		expected int
+	}{
+		{"TotalPresses", TotalPresses, 6},
+		{"DistinctGroups", DistinctGroups, 2},
+		{"GroupSwitches", GroupSwitches, 2},
+		{"AnimationTime", AnimationTime, 1*3 + 2*3 + 3*3},
+	}
+	for _, test := range tests {
+		if actual := test.cost(compass, steps); actual != test.expected {
+			t.Errorf("%s: unexpected cost %d (expected: %d)", test.name, actual, test.expected)
+		}
+	}
+}
+
+func TestGroupSwitches(t *testing.T) {
+	tests := []struct {
+		steps    Steps
+		expected int
+	}{
+		{nil, 0},
+		{Steps{{RingGroup: MiddleInner, Count: 3}}, 0},
+		// 转动次数为 0 的步骤不算切换
+		{Steps{{RingGroup: MiddleInner, Count: 1}, {RingGroup: OuterMiddle, Count: 0}, {RingGroup: MiddleInner, Count: 2}}, 0},
+		{Steps{{RingGroup: MiddleInner, Count: 1}, {RingGroup: OuterInner, Count: 2}, {RingGroup: MiddleInner, Count: 1}}, 2},
+		{Steps{{RingGroup: MiddleInner, Count: 1}, {RingGroup: MiddleInner, Count: 1}, {RingGroup: OuterInner, Count: 2}}, 1},
+	}
+	for _, test := range tests {
+		if actual := GroupSwitches(Compass{}, test.steps); actual != test.expected {
+			t.Errorf("%v: unexpected switches %d (expected: %d)", test.steps, actual, test.expected)
+		}
+	}
+}
+
+func TestSolver_Cost(t *testing.T) {
+	// 外圈转得快、内圈转得慢，最少转动次数与最短动画时长的解法不同
+	compass, err := ParseCompass("3+3,0+1,3+1/o,i,oi")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		cost     CostFunc
+		expected string
+	}{
+		{"TotalPresses", TotalPresses, "oi3"},
+		{"DistinctGroups", DistinctGroups, "oi3"},
+		{"AnimationTime", AnimationTime, "i2,oi1"},
+	}
+	for _, test := range tests {
+		for _, info := range Solvers() {
+			if !info.Optimal {
+				continue
+			}
+			solver, err := NewSolver(info.Name, SolverOptions{Cost: test.cost})
+			if err != nil {
+				t.Fatal(err)
+			}
+			solution, err := solver.Solve(context.Background(), compass)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if solution.String() != test.expected {
+				t.Errorf("%s/%s: unexpected solution %s (expected: %s)", info.Name, test.name, solution, test.expected)
+			}
+		}
+	}
+}
+
+func TestCostFuncByName(t *testing.T) {
+	for _, name := range CostFuncNames() {
+		if _, err := CostFuncByName(name); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+	}
+	if cost, err := CostFuncByName(DefaultCostName); err != nil || cost == nil {
+		t.Fatalf("unexpected default cost: %v", err)
+	}
+	if _, err := CostFuncByName("magic"); !errors.Is(err, ErrUnknownCost) {
+		t.Fatalf("unexpected error: %v (expected: %v)", err, ErrUnknownCost)
+	}
+}
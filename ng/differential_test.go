@@ -0,0 +1,180 @@
+package ng
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// differentialRingGroups 三圈罗盘中全部六种方案
+var differentialRingGroups = []RingGroup{Outer, Middle, Inner, OuterMiddle, OuterInner, MiddleInner}
+
+// differentialSpeeds 旋转速度 ±1 到 ±4
+var differentialSpeeds = []int{-4, -3, -2, -1, 1, 2, 3, 4}
+
+// forEachCompass 遍历使用 ringGroups 的全部三圈罗盘：各圈位置 0 到 5，旋转速度 ±1 到 ±4
+// stride 大于 1 时只遍历其中每 stride 个罗盘中的一个
+func forEachCompass(ringGroups []RingGroup, stride int, fn func(compass Compass)) {
+	n := 0
+	for l0 := 0; l0 < SCALES; l0++ {
+		for l1 := 0; l1 < SCALES; l1++ {
+			for l2 := 0; l2 < SCALES; l2++ {
+				for _, s0 := range differentialSpeeds {
+					for _, s1 := range differentialSpeeds {
+						for _, s2 := range differentialSpeeds {
+							n++
+							if n%stride != 0 {
+								continue
+							}
+							fn(Compass{
+								Rings: []Ring{
+									InnerRing:  {Location: l0, Speed: s0},
+									MiddleRing: {Location: l1, Speed: s1},
+									OuterRing:  {Location: l2, Speed: s2},
+								},
+								RingGroups: ringGroups,
+							})
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestSolvers_Differential 遍历全部三圈罗盘与方案组合，检查所有已注册的求解器对是否有解的判断一致，
+// 求出的解法都能通过 CheckSolution，并且求出最优解的求解器给出的解法转动总次数相同
+// 完整遍历约 220 万个罗盘，-short 时只抽取其中的一部分
+func TestSolvers_Differential(t *testing.T) {
+	stride := 1
+	if testing.Short() {
+		stride = 97
+	}
+
+	var solvers []differentialSolver
+	for _, info := range Solvers() {
+		if !info.Complete {
+			continue
+		}
+		solver, err := NewSolver(info.Name, SolverOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		solvers = append(solvers, differentialSolver{info: info, solver: solver})
+	}
+	if len(solvers) < 2 {
+		t.Skipf("only %d complete solvers registered", len(solvers))
+	}
+
+	for a := 0; a < len(differentialRingGroups); a++ {
+		for b := a + 1; b < len(differentialRingGroups); b++ {
+			for c := b + 1; c < len(differentialRingGroups); c++ {
+				ringGroups := []RingGroup{differentialRingGroups[a], differentialRingGroups[b], differentialRingGroups[c]}
+				name := fmt.Sprintf("%s,%s,%s", ringGroups[0], ringGroups[1], ringGroups[2])
+				t.Run(name, func(t *testing.T) {
+					failures := 0
+					forEachCompass(ringGroups, stride, func(compass Compass) {
+						if failures >= 10 {
+							return
+						}
+						if err := checkSolversAgree(compass, solvers); err != nil {
+							t.Errorf("%s: %v", compass.String(), err)
+							failures++
+						}
+					})
+				})
+			}
+		}
+	}
+}
+
+// differentialSolver 参与比较的求解器
+type differentialSolver struct {
+	info   SolverInfo
+	solver Solver
+}
+
+// checkSolversAgree 用所有求解器求解同一个罗盘，两两比较求解结果：
+// 所有求解器对是否有解的判断一致，求出最优解的求解器给出的解法代价相同
+func checkSolversAgree(compass Compass, solvers []differentialSolver) error {
+	solutions := make([]Steps, len(solvers))
+	errs := make([]error, len(solvers))
+	for i, s := range solvers {
+		solutions[i], errs[i] = solveAndCheck(compass, s.solver)
+		if errs[i] != nil && !errors.Is(errs[i], ErrNoSolution) {
+			return fmt.Errorf("%s: %w", s.info.Name, errs[i])
+		}
+	}
+	for i := 0; i < len(solvers); i++ {
+		for j := i + 1; j < len(solvers); j++ {
+			a, b := solvers[i].info, solvers[j].info
+			if (errs[i] == nil) != (errs[j] == nil) {
+				return fmt.Errorf("solvers disagree on solvability: %s: %v, %s: %v", a.Name, errs[i], b.Name, errs[j])
+			}
+			if errs[i] != nil || !a.Optimal || !b.Optimal {
+				continue
+			}
+			if costA, costB := TotalPresses(compass, solutions[i]), TotalPresses(compass, solutions[j]); costA != costB {
+				return fmt.Errorf("optimal solvers disagree on cost: %s: %s (%d), %s: %s (%d)",
+					a.Name, solutions[i].String(), costA, b.Name, solutions[j].String(), costB)
+			}
+		}
+	}
+	return nil
+}
+
+// solveAndCheck 求解罗盘，并检查求出的解法能否通过 CheckSolution
+func solveAndCheck(compass Compass, solver Solver) (Steps, error) {
+	solution, err := solver.Solve(context.Background(), compass)
+	if err != nil {
+		return nil, err
+	}
+	if ok, err := CheckSolution(compass, solution); err != nil || !ok {
+		return nil, fmt.Errorf("solution %s does not pass check: %v", solution.String(), err)
+	}
+	return solution, nil
+}
+
+// fixedSolver 总是返回固定结果的求解器，用于测试 checkSolversAgree
+type fixedSolver struct {
+	solution Steps
+	err      error
+}
+
+func (s fixedSolver) Solve(context.Context, Compass) (Steps, error) { return s.solution, s.err }
+
+func (s fixedSolver) AllSolutions(context.Context, Compass) ([]Steps, error) {
+	return []Steps{s.solution}, s.err
+}
+
+func TestCheckSolversAgree(t *testing.T) {
+	compass, err := ParseCompass("0+3,3-3,0+2/mi,om,oi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	short := fixedSolver{solution: Steps{{RingGroup: MiddleInner, Count: 3}}}
+	long := fixedSolver{solution: Steps{{RingGroup: MiddleInner, Count: 9}}}
+	none := fixedSolver{err: ErrNoSolution}
+	solver := func(name string, optimal bool, s Solver) differentialSolver {
+		return differentialSolver{info: SolverInfo{Name: name, Complete: true, Optimal: optimal}, solver: s}
+	}
+
+	tests := []struct {
+		name    string
+		solvers []differentialSolver
+		agree   bool
+	}{
+		{"same cost", []differentialSolver{solver("a", false, long), solver("b", true, short), solver("c", true, short)}, true},
+		// 第一个求解器不是最优求解器时，仍然需要比较其余的最优求解器
+		{"optimal solvers disagree on cost", []differentialSolver{solver("a", false, short), solver("b", true, short), solver("c", true, long)}, false},
+		{"non-optimal solver is longer", []differentialSolver{solver("a", true, short), solver("b", false, long)}, true},
+		// 两个后面的求解器之间对是否有解的判断不一致
+		{"solvability", []differentialSolver{solver("a", false, short), solver("b", true, short), solver("c", true, none)}, false},
+	}
+	for _, test := range tests {
+		if err := checkSolversAgree(compass, test.solvers); (err == nil) != test.agree {
+			t.Errorf("%s: unexpected result %v", test.name, err)
+		}
+	}
+}
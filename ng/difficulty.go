@@ -0,0 +1,169 @@
+package ng
+
+import (
+	"context"
+	"fmt"
+)
+
+// Level 难度等级
+type Level int
+
+// 难度等级
+const (
+	Easy Level = iota
+	Medium
+	Hard
+)
+
+// String 返回难度等级的名称
+func (l Level) String() string {
+	switch l {
+	case Easy:
+		return "easy"
+	case Medium:
+		return "medium"
+	case Hard:
+		return "hard"
+	default:
+		return fmt.Sprintf("Level(%d)", int(l))
+	}
+}
+
+// MarshalText 实现 encoding.TextMarshaler 接口
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// 难度分数的阈值，分数不超过 EasyScore 为 Easy，不超过 MediumScore 为 Medium，否则为 Hard
+// 注意：阈值还没有用游戏内的谜题标定，目前只对照过 catalog 包中 README 记录的两个谜题，
+// 游戏内谜题的目录补充之后需要重新检查阈值
+const (
+	EasyScore   = 4
+	MediumScore = 8
+)
+
+// Difficulty 罗盘的难度评估
+type Difficulty struct {
+	Level Level `json:"level"`
+	Score int   `json:"score"`
+	// 最优解法的转动总次数
+	Presses int `json:"presses"`
+	// 一个周期内转动总次数最少的解法数量
+	OptimalSolutions int `json:"optimalSolutions"`
+	// 最优解法中最少需要组合使用的方案数量
+	Groups int `json:"groups"`
+	// 逐个圈归位的贪心策略能否完成罗盘，参见 greedySolvable
+	Greedy bool `json:"greedy"`
+}
+
+// Rate 评估罗盘的难度，罗盘无解时返回 ErrNoSolution
+//
+// 难度分数按以下公式计算：
+//
+//	Score = Presses + 2 × (Groups - 1) + 3 × [贪心策略不可行] + 1 × [最优解法唯一]
+//
+// 每多转动一次加 1 分；每多组合一个方案加 2 分；玩家直觉上的「逐个圈归位」无法完成时加 3 分；
+// 最优解法唯一时容错更少，加 1 分。已经完成的罗盘为 0 分。
+// 分数不超过 EasyScore 为 Easy，不超过 MediumScore 为 Medium，否则为 Hard
+func Rate(compass Compass) (Difficulty, error) {
+	solver, err := NewLinearSolver(SolverOptions{Cost: TotalPresses})
+	if err != nil {
+		return Difficulty{}, err
+	}
+	solutions, err := solver.AllSolutions(context.Background(), compass)
+	if err != nil {
+		return Difficulty{}, err
+	}
+	if len(solutions) == 0 {
+		return Difficulty{}, ErrNoSolution
+	}
+
+	d := Difficulty{Presses: solutions[0].Total()}
+	if d.Presses == 0 {
+		d.Greedy = true
+		return d, nil
+	}
+
+	// 解法按转动总次数从少到多排列
+	d.Groups = len(compass.Rings) + 1
+	for _, solution := range solutions {
+		if solution.Total() != d.Presses {
+			break
+		}
+		d.OptimalSolutions++
+		d.Groups = min(d.Groups, DistinctGroups(compass, solution))
+	}
+	d.Greedy = greedySolvable(compass)
+
+	d.Score = d.Presses + 2*(d.Groups-1)
+	if !d.Greedy {
+		d.Score += 3
+	}
+	if d.OptimalSolutions == 1 {
+		d.Score++
+	}
+	switch {
+	case d.Score <= EasyScore:
+		d.Level = Easy
+	case d.Score <= MediumScore:
+		d.Level = Medium
+	default:
+		d.Level = Hard
+	}
+	return d, nil
+}
+
+// greedySolvable 判断「逐个圈归位」的贪心策略能否完成罗盘
+// 每一步选择一个尚未归位的圈，只使用一个不会转动已归位的圈的方案，把它转到目标位置；
+// 所有圈都经过这样的处理，或者剩余的圈恰好都停在目标位置时，策略可行。
+// 尝试所有的圈与方案的选择顺序，只要存在一种可行的顺序即返回 true
+func greedySolvable(compass Compass) bool {
+	scales := compass.ScaleCount()
+	ringGroups := uniqueRingGroups(compass.RingGroups)
+	locations := make([]int, len(compass.Rings))
+	for i, ring := range compass.Rings {
+		locations[i] = Mod(ring.Location, scales)
+	}
+
+	var walk func(fixed RingGroup) bool
+	walk = func(fixed RingGroup) bool {
+		done := true
+		for i, ring := range compass.Rings {
+			if locations[i] != Mod(ring.Target, scales) {
+				done = false
+			}
+		}
+		if done {
+			return true
+		}
+
+		for i, ring := range compass.Rings {
+			if fixed.Contains(i) {
+				continue
+			}
+			for _, rg := range ringGroups {
+				if !rg.Contains(i) || rg&fixed != 0 {
+					continue
+				}
+				movement := compass.Movement(rg)
+				for k := 1; k < scales; k++ {
+					if Mod(locations[i]+k*movement[i], scales) != Mod(ring.Target, scales) {
+						continue
+					}
+					for r, move := range movement {
+						locations[r] = Mod(locations[r]+k*move, scales)
+					}
+					ok := walk(fixed | 1<<i)
+					for r, move := range movement {
+						locations[r] = Mod(locations[r]-k*move, scales)
+					}
+					if ok {
+						return true
+					}
+				}
+			}
+		}
+		return false
+	}
+	return walk(0)
+}
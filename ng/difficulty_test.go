@@ -0,0 +1,69 @@
+package ng
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func ExampleRate() {
+	compass, err := ParseCompass("0+3,3-3,0+2/mi,om,oi")
+	if err != nil {
+		panic(err)
+	}
+	difficulty, err := Rate(compass)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(difficulty.Level, difficulty.Score)
+	// Output:
+	// easy 3
+}
+
+// TestRate_Formula 使用构造的罗盘覆盖评分公式中的每一项，游戏内谜题的评级参见 catalog 包的 TestDefault_Rate
+func TestRate_Formula(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   Difficulty
+	}{
+		// 已经完成的罗盘
+		{"0+3,3-3>3,0+2/mi,mo,io", Difficulty{Level: Easy, Score: 0, Greedy: true}},
+		{"0+1,0+1,5+1/i,o,om", Difficulty{Level: Easy, Score: 2, Presses: 1, OptimalSolutions: 1, Groups: 1, Greedy: true}},
+		{"5+1,2-1,4-2/mi,o,oi", Difficulty{Level: Medium, Score: 6, Presses: 3, OptimalSolutions: 1, Groups: 2, Greedy: true}},
+		{"4+2,3+3,5-1/m,mi,oi", Difficulty{Level: Medium, Score: 8, Presses: 5, OptimalSolutions: 1, Groups: 2, Greedy: true}},
+		{"2+1,2-2,4+2/o,oi,om", Difficulty{Level: Hard, Score: 9, Presses: 4, OptimalSolutions: 1, Groups: 3, Greedy: true}},
+		// 逐个圈归位无法完成，必须同时使用三个方案
+		{"0+2,4-4,0+1/mi,oi,om", Difficulty{Level: Hard, Score: 15, Presses: 8, OptimalSolutions: 2, Groups: 3, Greedy: false}},
+	}
+	for _, test := range tests {
+		compass, err := ParseCompass(test.expression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		difficulty, err := Rate(compass)
+		if err != nil {
+			t.Fatalf("%s: %v", test.expression, err)
+		}
+		if difficulty != test.expected {
+			t.Errorf("%s: unexpected difficulty %+v (expected: %+v)", test.expression, difficulty, test.expected)
+		}
+	}
+}
+
+func TestRate_NoSolution(t *testing.T) {
+	compass, err := ParseCompass("1+2,0+2,0+2/om,oi,mi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Rate(compass); !errors.Is(err, ErrNoSolution) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestLevel_String(t *testing.T) {
+	for level, expected := range map[Level]string{Easy: "easy", Medium: "medium", Hard: "hard", Level(7): "Level(7)"} {
+		if level.String() != expected {
+			t.Errorf("unexpected name %q (expected: %q)", level.String(), expected)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package ng
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+)
+
+// InGameSpeeds 游戏内引航罗盘各圈的旋转速度：顺时针或逆时针转动 ∠60°、∠120°、∠180°、∠240°，与 Ring.Speed 的有效范围 1-4 一致
+var InGameSpeeds = []int{-4, -3, -2, -1, 1, 2, 3, 4}
+
+// ErrGenerateFailed 在尝试次数内没有生成满足条件的罗盘
+var ErrGenerateFailed = errors.New(`failed to generate a compass satisfying the options`)
+
+// GenerateOptions 生成罗盘的选项，零值表示生成游戏内常见的三圈、三个方案的罗盘
+type GenerateOptions struct {
+	// 圈数，为 0 时使用 3
+	Rings int
+	// 总刻度值，为 0 时使用默认值 SCALES
+	Scales int
+	// 指定罗盘的方案，为空时随机选取 GroupCount 个包含一个或两个圈的方案
+	RingGroups []RingGroup
+	// 随机选取的方案数量，为 0 时使用 3
+	GroupCount int
+	// 各圈旋转速度的取值范围，为空时使用 InGameSpeeds 中绝对值小于总刻度值的速度
+	Speeds []int
+	// 最优解法（转动总次数最少的解法）至少需要转动的次数，为 0 时使用 1，即不会生成已经完成的罗盘
+	MinPresses int
+	// 最优解法最多需要转动的次数，为 0 时不限制
+	MaxPresses int
+	// 为 true 时只生成一个周期内只有唯一解法的罗盘
+	Unique bool
+	// 最多尝试生成的次数，为 0 时使用 10000
+	MaxAttempts int
+}
+
+// Generate 随机生成有解并且满足选项中全部条件的罗盘
+// 不断随机生成各圈的位置、速度以及方案，交给线性代数求解器检查，直到满足条件或者超过尝试次数
+// 使用相同种子的 r 会生成相同的罗盘
+func Generate(r *rand.Rand, opts GenerateOptions) (Compass, error) {
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return Compass{}, err
+	}
+	solver, err := NewLinearSolver(SolverOptions{Cost: TotalPresses})
+	if err != nil {
+		return Compass{}, err
+	}
+	candidates := candidateRingGroups(opts.Rings)
+
+	for attempt := 0; attempt < opts.MaxAttempts; attempt++ {
+		compass := Compass{Rings: make([]Ring, opts.Rings), RingGroups: opts.RingGroups}
+		if opts.Scales != SCALES {
+			compass.Scales = opts.Scales
+		}
+		for i := range compass.Rings {
+			compass.Rings[i] = Ring{Location: r.Intn(opts.Scales), Speed: opts.Speeds[r.Intn(len(opts.Speeds))]}
+		}
+		if len(compass.RingGroups) == 0 {
+			compass.RingGroups = pickRingGroups(r, candidates, opts.GroupCount)
+		}
+
+		solutions, err := solver.AllSolutions(context.Background(), compass)
+		if err != nil {
+			return Compass{}, err
+		}
+		if len(solutions) == 0 || (opts.Unique && len(solutions) != 1) {
+			continue
+		}
+		presses := solutions[0].Total()
+		if presses < opts.MinPresses || (opts.MaxPresses > 0 && presses > opts.MaxPresses) {
+			continue
+		}
+		return compass, nil
+	}
+
+	return Compass{}, fmt.Errorf(`%w after %d attempts`, ErrGenerateFailed, opts.MaxAttempts)
+}
+
+// withDefaults 检查选项并填充默认值
+func (opts GenerateOptions) withDefaults() (GenerateOptions, error) {
+	if opts.Rings == 0 {
+		opts.Rings = 3
+	}
+	if opts.Scales == 0 {
+		opts.Scales = SCALES
+	}
+	if opts.GroupCount == 0 {
+		opts.GroupCount = 3
+	}
+	if opts.MinPresses == 0 {
+		opts.MinPresses = 1
+	}
+	if opts.MaxAttempts == 0 {
+		opts.MaxAttempts = 10000
+	}
+	if len(opts.Speeds) == 0 {
+		for _, speed := range InGameSpeeds {
+			if Abs(speed) < opts.Scales {
+				opts.Speeds = append(opts.Speeds, speed)
+			}
+		}
+	}
+
+	if opts.Rings < 1 || opts.Rings > MaxRings {
+		return opts, fmt.Errorf("ring count must be between 1 and %d, got %d", MaxRings, opts.Rings)
+	}
+	if opts.Scales < 2 {
+		return opts, fmt.Errorf("scales must be at least 2, got %d", opts.Scales)
+	}
+	for _, speed := range opts.Speeds {
+		if speed == 0 {
+			return opts, errors.New("speeds must not contain 0")
+		}
+	}
+	for _, rg := range opts.RingGroups {
+		if rg == 0 || rg>>opts.Rings > 0 {
+			return opts, fmt.Errorf("ring group %s is out of range for a compass with %d rings", rg.Format(opts.Rings), opts.Rings)
+		}
+	}
+	if len(opts.RingGroups) == 0 && (opts.GroupCount < 1 || opts.GroupCount > len(candidateRingGroups(opts.Rings))) {
+		return opts, fmt.Errorf("group count must be between 1 and %d, got %d", len(candidateRingGroups(opts.Rings)), opts.GroupCount)
+	}
+	if opts.MaxPresses > 0 && opts.MaxPresses < opts.MinPresses {
+		return opts, fmt.Errorf("max presses %d is less than min presses %d", opts.MaxPresses, opts.MinPresses)
+	}
+	return opts, nil
+}
+
+// candidateRingGroups 返回包含一个或两个圈的全部方案，与游戏内的方案一致
+func candidateRingGroups(rings int) []RingGroup {
+	groups := make([]RingGroup, 0, rings*(rings+1)/2)
+	for i := 0; i < rings; i++ {
+		groups = append(groups, RingGroup(1<<i))
+		for j := i + 1; j < rings; j++ {
+			groups = append(groups, RingGroup(1<<i|1<<j))
+		}
+	}
+	return groups
+}
+
+// pickRingGroups 从候选方案中随机选取 n 个不同的方案
+func pickRingGroups(r *rand.Rand, candidates []RingGroup, n int) []RingGroup {
+	picked := make([]RingGroup, n)
+	for i, k := range r.Perm(len(candidates))[:n] {
+		picked[i] = candidates[k]
+	}
+	return picked
+}
@@ -0,0 +1,132 @@
+package ng
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name string
+		opts GenerateOptions
+	}{
+		{"default", GenerateOptions{}},
+		{"min presses", GenerateOptions{MinPresses: 8}},
+		{"max presses", GenerateOptions{MinPresses: 2, MaxPresses: 3}},
+		{"unique", GenerateOptions{Unique: true, MinPresses: 4}},
+		{"ring groups", GenerateOptions{RingGroups: []RingGroup{MiddleInner, OuterMiddle, OuterInner}}},
+		{"speeds", GenerateOptions{Speeds: []int{-1, 2}}},
+		{"generalized", GenerateOptions{Rings: 4, Scales: 8, GroupCount: 4, MinPresses: 5}},
+	}
+	hunger, err := NewHungerSolver(SolverOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			for i := 0; i < 20; i++ {
+				compass, err := Generate(r, test.opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := compass.Validate(); err != nil {
+					t.Fatalf("%s: %v", compass.String(), err)
+				}
+
+				// 使用另一个求解器检查生成的罗盘
+				solutions, err := hunger.AllSolutions(context.Background(), compass)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(solutions) == 0 {
+					t.Fatalf("%s: expected a solvable compass", compass.String())
+				}
+				presses := solutions[0].Total()
+				if presses < max(test.opts.MinPresses, 1) || (test.opts.MaxPresses > 0 && presses > test.opts.MaxPresses) {
+					t.Fatalf("%s: unexpected optimal solution %s", compass.String(), solutions[0].Format(len(compass.Rings)))
+				}
+				if test.opts.Unique && len(solutions) != 1 {
+					t.Fatalf("%s: expected a unique solution, got %d", compass.String(), len(solutions))
+				}
+				if test.opts.RingGroups != nil && !reflect.DeepEqual(compass.RingGroups, test.opts.RingGroups) {
+					t.Fatalf("%s: unexpected ring groups", compass.String())
+				}
+				for _, ring := range compass.Rings {
+					if !containsInt(test.opts.Speeds, ring.Speed) && (test.opts.Speeds != nil || !containsInt(InGameSpeeds, ring.Speed)) {
+						t.Fatalf("%s: unexpected speed %d", compass.String(), ring.Speed)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGenerate_Deterministic(t *testing.T) {
+	a, err := Generate(rand.New(rand.NewSource(42)), GenerateOptions{MinPresses: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Generate(rand.New(rand.NewSource(42)), GenerateOptions{MinPresses: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.String() != b.String() {
+		t.Fatalf("expected the same compass, got %s and %s", a.String(), b.String())
+	}
+}
+
+func TestGenerate_Invalid(t *testing.T) {
+	tests := []GenerateOptions{
+		{Rings: 9},
+		{Scales: 1},
+		{Speeds: []int{0}},
+		{RingGroups: []RingGroup{0b1000}},
+		{GroupCount: 7},
+		{MinPresses: 5, MaxPresses: 3},
+	}
+	for _, opts := range tests {
+		if _, err := Generate(rand.New(rand.NewSource(1)), opts); err == nil {
+			t.Fatalf("expected an error for %+v", opts)
+		}
+	}
+
+	// 一个周期内最多转动 3 × 5 = 15 次，不可能要求更多
+	_, err := Generate(rand.New(rand.NewSource(1)), GenerateOptions{MinPresses: 16, MaxAttempts: 100})
+	if !errors.Is(err, ErrGenerateFailed) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// containsInt 判断 values 中是否包含 v
+func containsInt(values []int, v int) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func TestGenerate_InGameSpeeds(t *testing.T) {
+	// 默认的速度包括 ±4，与 Ring.Speed 的有效范围一致
+	r := rand.New(rand.NewSource(1))
+	seen := map[int]bool{}
+	for i := 0; i < 200; i++ {
+		compass, err := Generate(r, GenerateOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, ring := range compass.Rings {
+			seen[ring.Speed] = true
+		}
+	}
+	for _, speed := range InGameSpeeds {
+		if !seen[speed] {
+			t.Errorf("speed %d is never generated", speed)
+		}
+	}
+}
@@ -2,71 +2,149 @@ package ng
 
 import (
 	"context"
-	"errors"
 	"fmt"
+
 	"github.com/go-logr/logr"
-	"sort"
 )
 
+func init() {
+	RegisterSolver("hunger", NewHungerSolver, SolverInfo{
+		Description: "brute force over every press count in one period",
+		Complete:    true,
+		Optimal:     true,
+		Generalized: true,
+	})
+}
+
 // NewHungerSolver 创建穷举求解器
 func NewHungerSolver(opts SolverOptions) (Solver, error) {
-	return &hungerSolver{logger: opts.Logger}, nil
+	return &hungerSolver{logger: opts.Logger, cost: costOrDefault(opts.Cost)}, nil
 }
 
 // hungerSolver 穷举求解器的实现
 type hungerSolver struct {
 	logger logr.Logger
+	cost   CostFunc
 }
 
 var _ Solver = &hungerSolver{}
 
 // Solve 求解引航罗盘
 func (s *hungerSolver) Solve(ctx context.Context, compass Compass) (Steps, error) {
+	progress, err := s.search(ctx, compass, nil)
+	if err != nil {
+		return nil, err
+	}
+	if progress.Found == 0 {
+		return nil, ErrNoSolution
+	}
+	return progress.Best, nil
+}
+
+// AllSolutions 求出一个周期内全部不同的标准化解法
+func (s *hungerSolver) AllSolutions(ctx context.Context, compass Compass) ([]Steps, error) {
+	solutions := make([]Steps, 0)
+	_, err := s.search(ctx, compass, func(solution Steps) {
+		solutions = append(solutions, solution)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sortSolutions(compass, s.cost, solutions), nil
+}
+
+// search 对所有可能的解法逐个试错，记录代价最小的解法，并将每个可行的解法交给 visit 处理
+// 每尝试一个解法前都会检查 ctx，被取消或超时时返回携带搜索进度的 *SearchError
+func (s *hungerSolver) search(ctx context.Context, compass Compass, visit func(Steps)) (SearchProgress, error) {
 	if err := compass.Validate(); err != nil {
-		return nil, fmt.Errorf(`invalid compass, error: %w`, err)
+		return SearchProgress{}, fmt.Errorf(`invalid compass, error: %w`, err)
+	}
+	if !compass.Constraints.IsZero() {
+		return SearchProgress{}, ErrUnsupportedConstraints
 	}
 
-	// 对所有可能的解法试错
-	for _, solution := range s.getPossibleSolutions(compass) {
-		if ok, _ := CheckSolution(compass, solution); ok {
-			return solution.Standardize(), nil
+	candidates := newCandidateIterator(uniqueRingGroups(compass.RingGroups), compass.ScaleCount())
+	progress := SearchProgress{Total: candidates.Total()}
+	for {
+		if err := ctx.Err(); err != nil {
+			return progress, &SearchError{Progress: progress, Err: err}
 		}
-		s.logger.V(1).Info(fmt.Sprintf(`try solution "%s" failed`, solution.String()))
-	}
 
-	return nil, errors.New(`the compass has no solution`)
-}
+		solution, ok := candidates.Next()
+		if !ok {
+			break
+		}
+		progress.Tried++
 
-// getPossibleSolutions 获取所有可能的解法
-func (s *hungerSolver) getPossibleSolutions(compass Compass) []Steps {
-	possibleSolutions := make([]Steps, 0)
-
-	for _, rg := range compass.RingGroups {
-		temp := make([]Steps, 0)
-		// 转动 6 次保证任何方案都可以回归原点
-		for i := 0; i < SCALES; i++ {
-			if len(possibleSolutions) == 0 {
-				temp = append(temp, Steps{{RingGroup: rg, Count: i}})
-				continue
-			}
-			for _, cur := range possibleSolutions {
-				temp = append(temp, append(cur, Step{RingGroup: rg, Count: i}))
+		if ok, _ := CheckSolution(compass, solution); !ok {
+			// 日志未开启时跳过格式化，避免遍历大量罗盘时的额外开销
+			if log := s.logger.V(1); log.Enabled() {
+				log.Info(fmt.Sprintf(`try solution "%s" failed`, solution.Format(len(compass.Rings))))
 			}
+			continue
 		}
-		possibleSolutions = temp
-	}
 
-	sort.SliceStable(possibleSolutions, func(i, j int) bool {
-		sumI := 0
-		for _, step := range possibleSolutions[i] {
-			sumI += step.Count
+		solution = solution.Standardize()
+		if progress.Found == 0 || lessSolution(compass, s.cost, solution, progress.Best) {
+			progress.Best = solution
 		}
-		sumJ := 0
-		for _, step := range possibleSolutions[j] {
-			sumJ += step.Count
+		progress.Found++
+		if visit != nil {
+			visit(solution)
 		}
-		return sumI < sumJ
-	})
+	}
+
+	return progress, nil
+}
+
+// candidateIterator 按需生成所有可能的解法
+// 每个方案转动 0 到 scales-1 次，转动 scales 次保证任何方案都可以回归原点
+type candidateIterator struct {
+	ringGroups []RingGroup
+	scales     int
+	counts     []int
+	done       bool
+}
+
+// newCandidateIterator 创建可能解法的迭代器
+func newCandidateIterator(ringGroups []RingGroup, scales int) *candidateIterator {
+	return &candidateIterator{
+		ringGroups: ringGroups,
+		scales:     scales,
+		counts:     make([]int, len(ringGroups)),
+	}
+}
+
+// Total 返回可能解法的总数
+func (it *candidateIterator) Total() int {
+	total := 1
+	for range it.ringGroups {
+		total *= it.scales
+	}
+	return total
+}
+
+// Next 返回下一个可能的解法，全部生成完毕后返回 false
+func (it *candidateIterator) Next() (Steps, bool) {
+	if it.done {
+		return nil, false
+	}
+
+	steps := make(Steps, len(it.ringGroups))
+	for i, rg := range it.ringGroups {
+		steps[i] = Step{RingGroup: rg, Count: it.counts[i]}
+	}
+
+	// 像里程表一样进位
+	it.done = true
+	for i := range it.counts {
+		it.counts[i]++
+		if it.counts[i] < it.scales {
+			it.done = false
+			break
+		}
+		it.counts[i] = 0
+	}
 
-	return possibleSolutions
+	return steps, true
 }
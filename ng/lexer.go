@@ -0,0 +1,89 @@
+package ng
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// tokenKind 表达式中词法单元的类型
+type tokenKind int
+
+const (
+	tokenEOF     tokenKind = iota // 表达式结束
+	tokenIllegal                  // 无法识别的字符
+	tokenNumber                   // 非负整数，例如 12
+	tokenSign                     // + 或 -
+	tokenName                     // 由字母组成的圈的缩写或别名，例如 mi、outer、内
+	tokenComma                    // , 或全角的 ，
+	tokenSlash                    // /
+	tokenAt                       // @
+	tokenGreater                  // >
+	tokenOpen                     // [ 或 (
+	tokenClose                    // ] 或 )
+)
+
+// punctuations 单个字符构成的词法单元
+var punctuations = map[rune]tokenKind{
+	'+': tokenSign,
+	'-': tokenSign,
+	',': tokenComma,
+	'，': tokenComma,
+	'/': tokenSlash,
+	'@': tokenAt,
+	'>': tokenGreater,
+	'[': tokenOpen,
+	'(': tokenOpen,
+	']': tokenClose,
+	')': tokenClose,
+}
+
+// token 词法单元
+type token struct {
+	kind tokenKind
+	text string
+	// 词法单元在表达式中的列号，从 1 开始，按字符计数
+	column int
+}
+
+// String 返回词法单元在错误信息中的表述
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of input"
+	}
+	return fmt.Sprintf(`"%s"`, t.text)
+}
+
+// tokenize 将表达式切分为词法单元，忽略词法单元之间的空白字符，结果总是以 tokenEOF 结尾
+func tokenize(expression string) []token {
+	input := []rune(expression)
+	tokens := make([]token, 0, len(input)+1)
+	for cursor := 0; ; {
+		for cursor < len(input) && unicode.IsSpace(input[cursor]) {
+			cursor++
+		}
+		if cursor >= len(input) {
+			return append(tokens, token{kind: tokenEOF, column: cursor + 1})
+		}
+
+		start := cursor
+		kind, ok := punctuations[input[cursor]]
+		switch {
+		case ok:
+			cursor++
+		case input[cursor] >= '0' && input[cursor] <= '9':
+			kind = tokenNumber
+			for cursor < len(input) && input[cursor] >= '0' && input[cursor] <= '9' {
+				cursor++
+			}
+		case unicode.IsLetter(input[cursor]):
+			kind = tokenName
+			for cursor < len(input) && unicode.IsLetter(input[cursor]) {
+				cursor++
+			}
+		default:
+			kind = tokenIllegal
+			cursor++
+		}
+		tokens = append(tokens, token{kind: kind, text: string(input[start:cursor]), column: start + 1})
+	}
+}
@@ -0,0 +1,30 @@
+package ng
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tokens := tokenize(" 0+12>3，外中 / [mi]@8 ?")
+	expected := []token{
+		{tokenNumber, "0", 2},
+		{tokenSign, "+", 3},
+		{tokenNumber, "12", 4},
+		{tokenGreater, ">", 6},
+		{tokenNumber, "3", 7},
+		{tokenComma, "，", 8},
+		{tokenName, "外中", 9},
+		{tokenSlash, "/", 12},
+		{tokenOpen, "[", 14},
+		{tokenName, "mi", 15},
+		{tokenClose, "]", 17},
+		{tokenAt, "@", 18},
+		{tokenNumber, "8", 19},
+		{tokenIllegal, "?", 21},
+		{tokenEOF, "", 22},
+	}
+	if !reflect.DeepEqual(tokens, expected) {
+		t.Fatalf("unexpected tokens: %+v", tokens)
+	}
+}
@@ -0,0 +1,211 @@
+package ng
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/go-logr/logr"
+)
+
+func init() {
+	RegisterSolver("linear", NewLinearSolver, SolverInfo{
+		Description: "modular linear algebra via the Smith normal form",
+		Complete:    true,
+		Optimal:     true,
+		Generalized: true,
+	})
+}
+
+// NewLinearSolver 创建线性代数求解器
+// 将罗盘转换为模总刻度值的线性同余方程组，借助史密斯标准型消元求解，
+// 能够正确处理主元在模总刻度值下不可逆的情况
+func NewLinearSolver(opts SolverOptions) (Solver, error) {
+	return &linearSolver{logger: opts.Logger, cost: costOrDefault(opts.Cost)}, nil
+}
+
+// linearSolver 线性代数求解器的实现
+type linearSolver struct {
+	logger logr.Logger
+	cost   CostFunc
+}
+
+var _ Solver = &linearSolver{}
+
+// Solve 求解引航罗盘
+// 逐个检查一个周期内的解法，只保留代价最小的一个，不需要像 AllSolutions 那样保存全部解法
+func (s *linearSolver) Solve(ctx context.Context, compass Compass) (Steps, error) {
+	ringGroups, a, b, err := s.equations(compass)
+	if err != nil {
+		return nil, err
+	}
+	var progress SearchProgress
+	progress.Total, err = eachModularSolution(ctx, a, b, compass.ScaleCount(), func(counts []int) {
+		progress.Tried++
+		progress.Found++
+		// 已经完成的罗盘的解法为空，不能以 nil 判断是否已经有解法
+		if steps := newSteps(ringGroups, counts); progress.Found == 1 || lessSolution(compass, s.cost, steps, progress.Best) {
+			progress.Best = steps
+		}
+	})
+	if err != nil {
+		return nil, &SearchError{Progress: progress, Err: err}
+	}
+	s.logger.V(1).Info(fmt.Sprintf(`checked %d solutions in one period`, progress.Found))
+	if progress.Found == 0 {
+		return nil, ErrNoSolution
+	}
+	return progress.Best, nil
+}
+
+// AllSolutions 求出一个周期内全部不同的标准化解法
+func (s *linearSolver) AllSolutions(ctx context.Context, compass Compass) ([]Steps, error) {
+	ringGroups, a, b, err := s.equations(compass)
+	if err != nil {
+		return nil, err
+	}
+	var solutions []Steps
+	total, err := eachModularSolution(ctx, a, b, compass.ScaleCount(), func(counts []int) {
+		solutions = append(solutions, newSteps(ringGroups, counts))
+	})
+	if err != nil {
+		progress := SearchProgress{Tried: len(solutions), Total: total, Found: len(solutions)}
+		if len(solutions) > 0 {
+			progress.Best = sortSolutions(compass, s.cost, solutions)[0]
+		}
+		return nil, &SearchError{Progress: progress, Err: err}
+	}
+	s.logger.V(1).Info(fmt.Sprintf(`found %d solutions in one period`, len(solutions)))
+	return sortSolutions(compass, s.cost, solutions), nil
+}
+
+// equations 检查罗盘并将其转换为线性同余方程组，参见 compassEquations
+func (s *linearSolver) equations(compass Compass) ([]RingGroup, [][]int, []int, error) {
+	if err := compass.Validate(); err != nil {
+		return nil, nil, nil, fmt.Errorf(`invalid compass, error: %w`, err)
+	}
+	if !compass.Constraints.IsZero() {
+		return nil, nil, nil, ErrUnsupportedConstraints
+	}
+	ringGroups := uniqueRingGroups(compass.RingGroups)
+	a, b := compassEquations(compass, ringGroups)
+	return ringGroups, a, b, nil
+}
+
+// compassEquations 将罗盘转换为线性同余方程组 A · x ≡ b (mod scales)
+// 每一行对应一个圈（下标与 Compass.Rings 一致），每一列对应一个方案，x 是每个方案的转动次数
+func compassEquations(compass Compass, ringGroups []RingGroup) (a [][]int, b []int) {
+	scales := compass.ScaleCount()
+
+	a = make([][]int, len(compass.Rings))
+	for i := range a {
+		a[i] = make([]int, len(ringGroups))
+	}
+	for j, rg := range ringGroups {
+		for i, move := range compass.Movement(rg) {
+			a[i][j] = Mod(move, scales)
+		}
+	}
+
+	b = make([]int, len(compass.Rings))
+	for i, ring := range compass.Rings {
+		// 需要转动的刻度使圈停在目标位置
+		b[i] = Mod(ring.Target-ring.Location, scales)
+	}
+	return a, b
+}
+
+// eachModularSolution 逐个列出线性同余方程组 A · x ≡ b (mod m) 在一个周期内（每个分量在 [0, m) 内）的全部解，
+// 返回解的总数，超出 int 的范围时为 math.MaxInt；visit 返回后 x 会被复用
+// 解的数量随方案数量与秩之差指数增长，每列出一个解前都会检查 ctx，被取消时返回 ctx.Err()
+func eachModularSolution(ctx context.Context, a [][]int, b []int, m int, visit func(x []int)) (int, error) {
+	rows := len(a)
+	cols := 0
+	if rows > 0 {
+		cols = len(a[0])
+	}
+
+	// U · A · V = D，令 x = V · y，方程组化为 D · y ≡ U · b (mod m)
+	f := newSmithForm(a)
+	c := make([]int, rows)
+	for i := range c {
+		for k := range b {
+			c[i] += f.U[i][k] * b[k]
+		}
+		c[i] = Mod(c[i], m)
+	}
+
+	// 对角线之外的行必须满足 0 ≡ c (mod m)
+	for i := cols; i < rows; i++ {
+		if c[i] != 0 {
+			return 0, nil
+		}
+	}
+
+	// 逐个求解 d · y ≡ c (mod m) 的全部候选值
+	candidates := make([][]int, cols)
+	total := 1
+	for i := range candidates {
+		d := 0
+		if i < rows {
+			d = f.D[i][i]
+		}
+		ci := 0
+		if i < rows {
+			ci = c[i]
+		}
+		candidates[i] = solveCongruence(d, ci, m)
+		if len(candidates[i]) == 0 {
+			return 0, nil
+		}
+		if total > math.MaxInt/len(candidates[i]) {
+			total = math.MaxInt
+		} else {
+			total *= len(candidates[i])
+		}
+	}
+
+	// 组合候选值，并变换回 x = V · y
+	y := make([]int, cols)
+	x := make([]int, cols)
+	var walk func(i int) error
+	walk = func(i int) error {
+		if i == cols {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			for r := range x {
+				x[r] = 0
+				for k := range y {
+					x[r] += f.V[r][k] * y[k]
+				}
+				x[r] = Mod(x[r], m)
+			}
+			visit(x)
+			return nil
+		}
+		for _, v := range candidates[i] {
+			y[i] = v
+			if err := walk(i + 1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return total, walk(0)
+}
+
+// solveCongruence 求解 d · y ≡ c (mod m) 在 [0, m) 内的全部解
+func solveCongruence(d, c, m int) []int {
+	g := GCD(d, m)
+	if c%g != 0 {
+		return nil
+	}
+	step := m / g
+	y0 := Mod(c/g*ModInv(d/g, step), step)
+	ys := make([]int, g)
+	for k := range ys {
+		ys[k] = y0 + k*step
+	}
+	return ys
+}
@@ -0,0 +1,168 @@
+package ng
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLinearSolver_Solve(t *testing.T) {
+	solver, err := NewLinearSolver(SolverOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		expression string
+		expected   string
+	}{
+		// README 中旧版 GaussMatrix 无法求解的案例
+		{"0+3,3-3,0+2/mi,mo,io", "mi3"},
+		{"0+2,3-3,0+3/mi,om,oi", "om3"},
+		{"0+2,0+2,0+2/o,m,i", ""},
+	}
+	for _, test := range tests {
+		compass, err := ParseCompass(test.expression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		solution, err := solver.Solve(context.Background(), compass)
+		if err != nil {
+			t.Fatalf("%s: %v", test.expression, err)
+		}
+		if solution.String() != test.expected {
+			t.Errorf("%s: unexpected solution %q (expected: %q)", test.expression, solution.String(), test.expected)
+		}
+		if ok, err := CheckSolution(compass, solution); err != nil || !ok {
+			t.Errorf("%s: solution %q does not pass check: %v", test.expression, solution.String(), err)
+		}
+	}
+}
+
+func TestLinearSolver_NoSolution(t *testing.T) {
+	solver, err := NewLinearSolver(SolverOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 所有圈每次都转动偶数个刻度，奇数位置永远无法归位
+	compass, err := ParseCompass("1+2,0+2,0+2/om,oi,mi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := solver.Solve(context.Background(), compass); !errors.Is(err, ErrNoSolution) {
+		t.Fatalf("unexpected error: %v (expected: %v)", err, ErrNoSolution)
+	}
+}
+
+func TestLinearSolver_Cancel(t *testing.T) {
+	solver, err := NewLinearSolver(SolverOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 四个圈、十五个方案，一个周期内有 12^11 个解法，无法在超时之前全部检查
+	compass, err := ParseCompass("0+1,0+1,0+1,1+1/a,b,c,d,ab,ac,ad,bc,bd,cd,abc,abd,acd,bcd,abcd@12")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := &countdownContext{Context: context.Background(), remaining: 100}
+	_, err = solver.Solve(ctx, compass)
+	var searchErr *SearchError
+	if !errors.Is(err, context.Canceled) || !errors.As(err, &searchErr) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if progress := searchErr.Progress; progress.Tried != 100 || progress.Found != 100 || progress.Total != 743008370688 {
+		t.Fatalf("unexpected progress: %+v", progress)
+	}
+	if ok, err := CheckSolution(compass, searchErr.Progress.Best); err != nil || !ok {
+		t.Fatalf("best solution so far %s does not pass check: %v", searchErr.Progress.Best, err)
+	}
+
+	ctx = &countdownContext{Context: context.Background(), remaining: 10}
+	if _, err := solver.AllSolutions(ctx, compass); !errors.As(err, &searchErr) || searchErr.Progress.Tried != 10 {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	deadline, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := solver.Solve(deadline, compass); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("unexpected error: %v (expected: %v)", err, context.DeadlineExceeded)
+	}
+}
+
+func TestLinearSolver_AgreesWithHungerSolver(t *testing.T) {
+	linear, err := NewLinearSolver(SolverOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hunger, err := NewHungerSolver(SolverOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	groups := []RingGroup{MiddleInner, OuterMiddle, OuterInner}
+	speeds := []int{-4, -3, -2, -1, 1, 2, 3, 4}
+	for _, speed := range speeds {
+		for location := 0; location < SCALES; location++ {
+			compass := NewCompass(
+				Ring{Location: location, Speed: 2},
+				Ring{Location: 3, Speed: speed},
+				Ring{Location: (location + 1) % SCALES, Speed: 3},
+				groups...,
+			)
+			expected, expectedErr := hunger.Solve(context.Background(), compass)
+			actual, actualErr := linear.Solve(context.Background(), compass)
+			if (expectedErr == nil) != (actualErr == nil) {
+				t.Fatalf("%s: solvers disagree: hunger=%v, linear=%v", compass.String(), expectedErr, actualErr)
+			}
+			if expectedErr == nil && expected.Total() != actual.Total() {
+				t.Errorf("%s: solvers disagree on total count: hunger=%s, linear=%s", compass.String(), expected, actual)
+			}
+		}
+	}
+}
+
+func TestSmithForm(t *testing.T) {
+	a := [][]int{
+		{2, 0, 2},
+		{3, 3, 0},
+		{0, 3, 3},
+	}
+	f := newSmithForm(a)
+
+	// U · A · V == D
+	product := multiplyMatrix(multiplyMatrix(f.U, a), f.V)
+	for i := range product {
+		for j := range product[i] {
+			if product[i][j] != f.D[i][j] {
+				t.Fatalf("U·A·V != D: %v != %v", product, f.D)
+			}
+			if i != j && f.D[i][j] != 0 {
+				t.Fatalf("D is not diagonal: %v", f.D)
+			}
+		}
+	}
+
+	// 对角线元素依次整除
+	diagonal := f.Diagonal()
+	for i := 1; i < len(diagonal); i++ {
+		if diagonal[i-1] == 0 || diagonal[i]%diagonal[i-1] != 0 {
+			t.Fatalf("diagonal is not a divisor chain: %v", diagonal)
+		}
+	}
+}
+
+func multiplyMatrix(a, b [][]int) [][]int {
+	c := make([][]int, len(a))
+	for i := range a {
+		c[i] = make([]int, len(b[0]))
+		for j := range c[i] {
+			for k := range b {
+				c[i][j] += a[i][k] * b[k][j]
+			}
+		}
+	}
+	return c
+}
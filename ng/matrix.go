@@ -0,0 +1,130 @@
+package ng
+
+import "fmt"
+
+// CompassFromMatrix 将 README 中 GaussMatrix 使用的增广矩阵转换为罗盘，mod 为总刻度值
+// 矩阵的每一行是一个圈，从里到外排列，与 Compass.Rings 一致，经典三圈罗盘依次是内圈、中圈、外圈；
+// 除最后一列外，第 j 列是方案 j 转动一次时各圈转动的刻度，0 表示方案不转动这个圈；
+// 最后一列写作 mod - x，x 是圈当前停留的刻度，例如停在 0 时为 mod，停在 4 时为 mod - 4。
+//
+// 矩阵只记录方案转动各圈的刻度，因此每个圈的旋转速度取它所在的第一个方案中的值，
+// 其他方案中的值与之不同时记录为方案的旋转速度，参见 Compass.GroupSpeeds；
+// 不属于任何方案的圈无法得知旋转速度，使用 1
+func CompassFromMatrix(matrix [][]int, mod int) (Compass, error) {
+	compass := Compass{}
+	if mod < 2 {
+		return compass, fmt.Errorf(`mod must be at least 2, got %d`, mod)
+	}
+	if len(matrix) == 0 || len(matrix) > MaxRings {
+		return compass, fmt.Errorf(`matrix must have between 1 and %d rows, got %d`, MaxRings, len(matrix))
+	}
+	columns := len(matrix[0]) - 1
+	if columns < 1 {
+		return compass, fmt.Errorf(`matrix must have at least 2 columns, got %d`, columns+1)
+	}
+	if mod != SCALES {
+		compass.Scales = mod
+	}
+
+	compass.Rings = make([]Ring, len(matrix))
+	compass.RingGroups = make([]RingGroup, columns)
+	for i, row := range matrix {
+		if len(row) != columns+1 {
+			return compass, fmt.Errorf(`row %d of matrix must have %d columns, got %d`, i, columns+1, len(row))
+		}
+		if last := row[columns]; last < 1 || last > mod {
+			return compass, fmt.Errorf(`last column of row %d must be mod - x in range [1, %d], got %d`, i, mod, last)
+		}
+		compass.Rings[i].Location = mod - row[columns]
+		for j, move := range row[:columns] {
+			if move == 0 {
+				continue
+			}
+			if Abs(move) >= mod {
+				return compass, fmt.Errorf(`movement %+d at row %d, column %d is out of range [1, %d)`, move, i, j, mod)
+			}
+			compass.RingGroups[j] |= 1 << i
+			if compass.Rings[i].Speed == 0 {
+				compass.Rings[i].Speed = move
+			}
+		}
+		if compass.Rings[i].Speed == 0 {
+			compass.Rings[i].Speed = 1
+		}
+	}
+
+	for j, rg := range compass.RingGroups {
+		if rg == 0 {
+			return compass, fmt.Errorf(`column %d of matrix does not move any ring`, j)
+		}
+		// 两列是同一个方案时，转动各圈的刻度必须一致
+		for k := 0; k < j; k++ {
+			if compass.RingGroups[k] == rg && !equalColumns(matrix, j, k) {
+				return compass, fmt.Errorf(`columns %d and %d of matrix move the same rings by different scales`, k, j)
+			}
+		}
+
+		var speeds []int
+		for i, row := range matrix {
+			if row[j] == 0 || row[j] == compass.Rings[i].Speed {
+				continue
+			}
+			if speeds == nil {
+				speeds = make([]int, len(matrix))
+			}
+			speeds[i] = row[j]
+		}
+		if speeds == nil {
+			continue
+		}
+		if compass.GroupSpeeds == nil {
+			compass.GroupSpeeds = make(map[RingGroup][]int)
+		}
+		compass.GroupSpeeds[rg] = speeds
+	}
+
+	if err := compass.Validate(); err != nil {
+		return compass, fmt.Errorf(`invalid compass: %w`, err)
+	}
+	return compass, nil
+}
+
+// equalColumns 判断矩阵的两列是否相同
+func equalColumns(matrix [][]int, a, b int) bool {
+	for _, row := range matrix {
+		if row[a] != row[b] {
+			return false
+		}
+	}
+	return true
+}
+
+// ToMatrix 将罗盘转换为 GaussMatrix 使用的增广矩阵，格式参见 CompassFromMatrix，mod 为 ScaleCount 的返回值
+// 圈声明了目标位置时，最后一列为 mod - (x - target)，即把圈转到目标位置等价于从 x - target 转到 0
+func (c *Compass) ToMatrix() [][]int {
+	mod := c.ScaleCount()
+	matrix := make([][]int, len(c.Rings))
+	for i, ring := range c.Rings {
+		matrix[i] = make([]int, len(c.RingGroups)+1)
+		matrix[i][len(c.RingGroups)] = mod - Mod(ring.Location-ring.Target, mod)
+	}
+	for j, rg := range c.RingGroups {
+		for i, move := range c.Movement(rg) {
+			matrix[i][j] = move
+		}
+	}
+	return matrix
+}
+
+// StepsFromCounts 将 GaussMatrix.Guess 给出的一组解转换为标准化的解法
+// counts 是各方案的转动次数，下标与 Compass.RingGroups 一致，即矩阵的各列；转动次数按总刻度值取模
+func StepsFromCounts(compass Compass, counts []int) (Steps, error) {
+	if len(counts) != len(compass.RingGroups) {
+		return nil, fmt.Errorf(`expected %d counts, got %d`, len(compass.RingGroups), len(counts))
+	}
+	normalized := make([]int, len(counts))
+	for i, count := range counts {
+		normalized[i] = Mod(count, compass.ScaleCount())
+	}
+	return newSteps(compass.RingGroups, normalized), nil
+}
@@ -0,0 +1,126 @@
+package ng
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func ExampleCompassFromMatrix() {
+	// README 中的矩阵，各行依次是内圈、中圈、外圈
+	mod := 6
+	compass, err := CompassFromMatrix([][]int{
+		{-1, 0, -1, mod - 4},
+		{-3, -3, 0, mod - 0},
+		{0, 3, 3, mod - 0},
+	}, mod)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(compass.String())
+
+	// GaussMatrix.Guess 给出的解 [4 0 0]
+	steps, err := StepsFromCounts(compass, []int{4, 0, 0})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(steps.String())
+	// Output:
+	// 0+3,0-3,4-1/mi,oi,om
+	// mi4
+}
+
+func ExampleCompass_ToMatrix() {
+	compass, err := ParseCompass("0+3,0-3,4-1/mi,om,oi")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(compass.ToMatrix())
+	// Output:
+	// [[-1 0 -1 2] [-3 -3 0 6] [0 3 3 6]]
+}
+
+func TestCompassFromMatrix_RoundTrip(t *testing.T) {
+	expressions := []string{
+		"0+3,3-3,0+2/mi,om,oi",
+		"0+2,3-3,0+3/m+2i-1,om,oi",
+		"5+1,2-1,4-2/mi,o,oi",
+		"0+1,3-2,1+3,7+5/ab,dc,ad,b@8",
+		"3+1,2-1/a,b,ab",
+	}
+	for _, expression := range expressions {
+		compass, err := ParseCompass(expression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		matrix := compass.ToMatrix()
+		converted, err := CompassFromMatrix(matrix, compass.ScaleCount())
+		if err != nil {
+			t.Fatalf("%s: %v", expression, err)
+		}
+		// 矩阵中没有圈的旋转速度，声明了方案速度的罗盘只能保证矩阵一致
+		if compass.GroupSpeeds == nil && converted.String() != compass.String() {
+			t.Errorf("%s: unexpected compass %s", expression, converted.String())
+		}
+		if !reflect.DeepEqual(converted.ToMatrix(), matrix) {
+			t.Errorf("%s: unexpected matrix %v (expected: %v)", expression, converted.ToMatrix(), matrix)
+		}
+	}
+}
+
+func TestCompass_ToMatrix_Target(t *testing.T) {
+	// 外圈停在 1、目标位置为 3，等价于停在 1 - 3 = 4、目标位置为 0
+	compass, err := ParseCompass("1+3>3,3-3,0+2/mi,om,oi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	converted, err := CompassFromMatrix(compass.ToMatrix(), compass.ScaleCount())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if converted.String() != "4+3,3-3,0+2/mi,oi,om" {
+		t.Fatalf("unexpected compass %s", converted.String())
+	}
+}
+
+func TestCompassFromMatrix_Invalid(t *testing.T) {
+	tests := []struct {
+		matrix [][]int
+		mod    int
+	}{
+		{[][]int{{1, 6}}, 1},               // 刻度过少
+		{nil, 6},                           // 没有圈
+		{[][]int{{6}}, 6},                  // 没有方案
+		{[][]int{{1, 0, 6}, {1, 6}}, 6},    // 列数不一致
+		{[][]int{{1, 0}}, 6},               // mod - x 超出范围
+		{[][]int{{1, 7}}, 6},               // mod - x 超出范围
+		{[][]int{{6, 6}}, 6},               // 转动的刻度超出范围
+		{[][]int{{1, 0, 6}, {1, 0, 6}}, 6}, // 方案不转动任何圈
+		{[][]int{{1, 2, 6}, {1, 1, 6}}, 6}, // 同一个方案转动的刻度不一致
+		{[][]int{{1, 6}, {1, 6}, {1, 6}, {1, 6}, {1, 6}, {1, 6}, {1, 6}, {1, 6}, {1, 6}}, 6}, // 圈数过多
+	}
+	for _, test := range tests {
+		if compass, err := CompassFromMatrix(test.matrix, test.mod); err == nil {
+			t.Errorf("%v: expected error, got %s", test.matrix, compass.String())
+		}
+	}
+}
+
+func TestStepsFromCounts(t *testing.T) {
+	compass, err := ParseCompass("0+3,3-3,0+2/mi,om,oi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 表达式不允许重复的方案，这里直接在罗盘中追加
+	compass.RingGroups = append(compass.RingGroups, MiddleInner)
+	steps, err := StepsFromCounts(compass, []int{1, 0, -1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if steps.String() != "mi3,oi5" {
+		t.Fatalf("unexpected steps %s", steps.String())
+	}
+	if _, err := StepsFromCounts(compass, []int{1, 0, 0}); err == nil {
+		t.Fatal("expected error")
+	}
+}
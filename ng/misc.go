@@ -8,3 +8,38 @@ func Mod(x, mod int) int {
 	}
 	return ret
 }
+
+// Abs 返回整数的绝对值
+func Abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// GCD 返回 a、b 两数的最大公约数（非负）
+func GCD(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return Abs(a)
+}
+
+// EGCD 扩展欧几里得算法
+// 返回 a、b 两数的最大公约数 g 同时，找到 x、y，使他们满足贝祖等式 ax + by = GCD(a, b)
+func EGCD(a, b int) (g int, x int, y int) {
+	if b == 0 {
+		return a, 1, 0
+	}
+	g, x, y = EGCD(b, a%b)
+	return g, y, x - (a/b)*y
+}
+
+// ModInv 模数反转，返回 a 在模 mod 下的乘法逆元，不存在时返回 -1
+func ModInv(a, mod int) int {
+	g, x, _ := EGCD(Mod(a, mod), mod)
+	if g != 1 {
+		return -1 // 无解
+	}
+	return Mod(x, mod)
+}
@@ -2,26 +2,35 @@ package ng
 
 import (
 	"fmt"
-	"regexp"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
-const (
-	compassRegexpStr = `(?P<outerRing>[0-9-+]+),` +
-		`(?P<middleRing>[0-9-+]+),` +
-		`(?P<innerRing>[0-9-+]+)/` +
-		`(?P<ringGroups>[imo,]+)`
-	ringRegexpStr = `(?P<location>[0-5])(?P<speed>(?:\+|-)[1-4])`
-)
+// ParseError 解析表达式失败时返回的错误，记录出错的位置以及期望的内容
+type ParseError struct {
+	Expression string // 被解析的表达式
+	Column     int    // 出错位置的列号，从 1 开始，按字符计数
+	Expected   string // 期望的内容，例如 `"/"`、`ring speed`；内容超出范围等语义错误时为空
+	Found      string // 实际遇到的内容，例如 `"x"`、`end of input`
+	Err        error  // 语义错误的原因，语法错误时为 nil
+}
 
-var (
-	compassRegexp = regexp.MustCompile(compassRegexpStr)
-	ringRegexp    = regexp.MustCompile(ringRegexpStr)
-)
+// Error 实现 error 接口
+func (e *ParseError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf(`parse "%s" error at column %d: %v`, e.Expression, e.Column, e.Err)
+	}
+	return fmt.Sprintf(`parse "%s" error at column %d: expected %s, found %s`, e.Expression, e.Column, e.Expected, e.Found)
+}
+
+// Unwrap 返回语义错误的原因
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
 
 // ParseCompass 解析罗盘信息表达式
-// 罗盘信息表达式满足如下格式:
+// 经典三圈罗盘的信息表达式满足如下格式:
 //
 //	{oLoc}{oSpeed},{mLoc}{mSpeed},{iLoc}{iSpeed}/{rg1},{rg2},{rg3}
 //	{oLoc}, {mLoc}, {iLoc}：分别表示外圈、中圈、内圈的初始刻度
@@ -33,99 +42,476 @@ var (
 //		om 或 mo
 //		oi 或 io
 //		im 或 mi
+//
+// 其他圈数的罗盘按从外到内的顺序列出任意个圈，方案中从最外侧的圈开始依次使用 a、b、c ... 表示各圈，
+// 并且可以在末尾使用 @{scales} 声明总刻度值，未声明时为 SCALES，例如:
+//
+//	0+1,3-2,1+3,7+5/ab,cd,ad,b@8
+//
+// 每个圈都可以在旋转速度后使用 >{target} 声明目标位置，未声明时为 0，例如外圈需要停在刻度 3:
+//
+//	0+2>3,3-3,0+3/mi,om,oi
+//
+// 方案中的每个圈都可以在缩写后声明该方案转动这个圈的速度，未声明时使用圈的旋转速度，
+// 例如方案 A 转动中圈 2 个刻度、内圈 -1 个刻度，方案 B 使用中圈和外圈的旋转速度:
+//
+//	0+2,3-3,0+3/m+2i-1,om,oi
+//
+// 表达式的各部分之间可以插入空白字符，圈的列表与方案的列表都可以使用 [] 或 () 括起来，
+// 三圈罗盘的方案中还可以使用 outer、middle、inner 或 外、中、内 表示各圈，不区分大小写，例如:
+//
+//	[0+2, 3-3, 0+3] / [中内, OuterMiddle, outerinner]
+//
+// 表达式不合法时返回 *ParseError，其中记录了出错的列号与期望的内容
 func ParseCompass(expression string) (Compass, error) {
 	compass := Compass{}
+	p := newParser(expression)
 
-	// 正则解析获得捕获组
-	groups := compassRegexp.FindStringSubmatch(expression)
-	if len(groups) == 0 {
-		return compass, fmt.Errorf(`invalid compass expression: "%s" (not match "%s")`, expression, compassRegexpStr)
+	// 解析各圈，表达式中从外到内排列
+	var rings []ringNode
+	bracketed, err := p.list(func() error {
+		if len(rings) == MaxRings {
+			return p.errorAt(p.peek().column, fmt.Errorf(`too many rings (must be at most %d)`, MaxRings))
+		}
+		ring, err := p.ring()
+		if err != nil {
+			return err
+		}
+		rings = append(rings, ring)
+		return nil
+	})
+	if err != nil {
+		return compass, err
+	}
+	if _, err := p.expect(tokenSlash, listFollow(bracketed, `"/"`)); err != nil {
+		return compass, err
 	}
 
-	// 解析各捕获组的表达式
-	outer, err := parseRing(groups[compassRegexp.SubexpIndex("outerRing")])
+	// 解析转动方案
+	var groups []groupNode
+	bracketed, err = p.list(func() error {
+		group, err := p.ringGroup(len(rings))
+		if err != nil {
+			return err
+		}
+		groups = append(groups, group)
+		return nil
+	})
 	if err != nil {
-		return compass, fmt.Errorf(`parse outer ring error: %w`, err)
+		return compass, err
 	}
-	compass.OuterRing = outer
 
-	middle, err := parseRing(groups[compassRegexp.SubexpIndex("middleRing")])
+	// 解析总刻度值
+	expected := listFollow(bracketed, `"@" or end of input`)
+	if _, ok := p.accept(tokenAt); ok {
+		scales, column, err := p.number("scales")
+		if err != nil {
+			return compass, err
+		}
+		if scales < 2 {
+			return compass, p.errorAt(column, fmt.Errorf(`scales %d must be at least 2`, scales))
+		}
+		compass.Scales = scales
+		expected = "end of input"
+	}
+	if _, err := p.expect(tokenEOF, expected); err != nil {
+		return compass, err
+	}
+
+	// 总刻度值位于表达式末尾，解析完成后才能检查各圈与方案的速度是否超出范围
+	scales := compass.ScaleCount()
+	compass.Rings = make([]Ring, len(rings))
+	for i, ring := range rings {
+		index := len(rings) - 1 - i
+		if column, err := ring.check(scales); err != nil {
+			return compass, p.errorAt(column, fmt.Errorf(`parse %s ring error: %w`, RingName(index, len(rings)), err))
+		}
+		compass.Rings[index] = ring.Ring
+	}
+	for _, group := range groups {
+		for i, speed := range group.speeds {
+			if speed != 0 && Abs(speed) >= scales {
+				return compass, p.errorAt(group.columns[i], fmt.Errorf(`speed %+d of ring group %s is out of range [1, %d)`, speed, group.ringGroup.Format(len(rings)), scales))
+			}
+		}
+	}
+	compass.RingGroups, compass.GroupSpeeds, err = p.buildRingGroups(groups, len(rings))
 	if err != nil {
-		return compass, fmt.Errorf(`parse middle ring error: %w`, err)
+		return compass, err
+	}
+
+	return compass, nil
+}
+
+// listFollow 返回列表之后期望的内容，没有使用括号时还可以继续列出下一项
+func listFollow(bracketed bool, follow string) string {
+	if bracketed {
+		return follow
+	}
+	if strings.Contains(follow, " or ") {
+		return `",", ` + follow
+	}
+	return `"," or ` + follow
+}
+
+// parser 罗盘信息表达式的递归下降解析器
+type parser struct {
+	expression string
+	tokens     []token
+	cursor     int
+}
+
+// newParser 创建解析 expression 的解析器
+func newParser(expression string) *parser {
+	return &parser{expression: expression, tokens: tokenize(expression)}
+}
+
+// peek 返回下一个词法单元但不消耗它
+func (p *parser) peek() token {
+	return p.tokens[p.cursor]
+}
+
+// next 消耗并返回下一个词法单元，到达表达式末尾后总是返回 tokenEOF
+func (p *parser) next() token {
+	t := p.tokens[p.cursor]
+	if t.kind != tokenEOF {
+		p.cursor++
+	}
+	return t
+}
+
+// accept 下一个词法单元是 kind 类型时消耗并返回它
+func (p *parser) accept(kind tokenKind) (token, bool) {
+	if p.peek().kind != kind {
+		return token{}, false
 	}
-	compass.MiddleRing = middle
+	return p.next(), true
+}
 
-	inner, err := parseRing(groups[compassRegexp.SubexpIndex("innerRing")])
+// expect 消耗一个 kind 类型的词法单元，类型不符时返回期望 expected 的 *ParseError
+func (p *parser) expect(kind tokenKind, expected string) (token, error) {
+	if t, ok := p.accept(kind); ok {
+		return t, nil
+	}
+	return token{}, p.unexpected(expected)
+}
+
+// unexpected 返回下一个词法单元不是 expected 的 *ParseError
+func (p *parser) unexpected(expected string) *ParseError {
+	t := p.peek()
+	return &ParseError{Expression: p.expression, Column: t.column, Expected: expected, Found: t.String()}
+}
+
+// errorAt 返回位于 column 列的语义错误
+func (p *parser) errorAt(column int, err error) *ParseError {
+	return &ParseError{Expression: p.expression, Column: column, Err: err}
+}
+
+// number 解析非负整数，返回数值与列号
+func (p *parser) number(expected string) (int, int, error) {
+	t, err := p.expect(tokenNumber, expected)
 	if err != nil {
-		return compass, fmt.Errorf(`parse inner ring error: %w`, err)
+		return 0, 0, err
 	}
-	compass.InnerRing = inner
+	value, err := strconv.Atoi(t.text)
+	if err != nil {
+		return 0, 0, p.errorAt(t.column, fmt.Errorf(`invalid %s "%s": %w`, expected, t.text, err))
+	}
+	return value, t.column, nil
+}
 
-	ringGroups, err := parseRingGroups(groups[compassRegexp.SubexpIndex("ringGroups")])
+// signedNumber 解析带符号的整数，返回数值与符号的列号
+func (p *parser) signedNumber(expected string) (int, int, error) {
+	sign, err := p.expect(tokenSign, fmt.Sprintf(`"+" or "-" before %s`, expected))
+	if err != nil {
+		return 0, 0, err
+	}
+	value, _, err := p.number(expected)
 	if err != nil {
-		return compass, fmt.Errorf("parse ring groups error: %w", err)
+		return 0, 0, err
 	}
-	compass.RingGroups = ringGroups
+	if sign.text == "-" {
+		value = -value
+	}
+	return value, sign.column, nil
+}
 
-	return compass, nil
+// list 依次解析逗号分隔的各项，整个列表可以使用 [] 或 () 括起来，返回列表是否使用了括号
+func (p *parser) list(item func() error) (bool, error) {
+	open, bracketed := p.accept(tokenOpen)
+	for {
+		if err := item(); err != nil {
+			return false, err
+		}
+		if _, ok := p.accept(tokenComma); !ok {
+			break
+		}
+	}
+	if !bracketed {
+		return false, nil
+	}
+	closing := map[string]string{"[": "]", "(": ")"}[open.text]
+	if t := p.peek(); t.kind != tokenClose || t.text != closing {
+		return false, p.unexpected(fmt.Sprintf(`"," or "%s"`, closing))
+	}
+	p.next()
+	return true, nil
 }
 
-// parseRing 解析罗盘圈表达式
-func parseRing(expression string) (Ring, error) {
-	ring := Ring{}
+// ringNode 解析得到的圈，以及各部分在表达式中的列号
+type ringNode struct {
+	Ring
+	location, speed, target int
+}
+
+// ring 解析罗盘圈表达式 {location}{speed}[>{target}]
+func (p *parser) ring() (ringNode, error) {
+	var node ringNode
+	var err error
+	if node.Location, node.location, err = p.number("ring location"); err != nil {
+		return node, err
+	}
+	if node.Speed, node.speed, err = p.signedNumber("ring speed"); err != nil {
+		return node, err
+	}
+	if _, ok := p.accept(tokenGreater); ok {
+		if node.Target, node.target, err = p.number("ring target"); err != nil {
+			return node, err
+		}
+	}
+	return node, nil
+}
+
+// check 检查圈的位置、旋转速度与目标位置是否在总刻度值的范围内，返回出错部分的列号
+func (n ringNode) check(scales int) (int, error) {
+	if n.Location < 0 || n.Location >= scales {
+		return n.location, fmt.Errorf(`ring location %d is out of range [0, %d)`, n.Location, scales)
+	}
+	if n.Speed == 0 || Abs(n.Speed) >= scales {
+		return n.speed, fmt.Errorf(`ring speed %+d is out of range [1, %d)`, n.Speed, scales)
+	}
+	if n.Target < 0 || n.Target >= scales {
+		return n.target, fmt.Errorf(`ring target %d is out of range [0, %d)`, n.Target, scales)
+	}
+	return 0, nil
+}
+
+// groupNode 解析得到的方案，以及方案与其声明的各个速度在表达式中的列号
+type groupNode struct {
+	ringGroup RingGroup
+	speeds    []int // 下标与 Compass.Rings 一致，没有声明任何速度时为 nil
+	columns   []int // 各个速度的列号，下标与 speeds 一致
+	column    int
+}
+
+// ringAliases 三圈罗盘中各圈的名称，较长的名称排在前面，下标为圈在 Compass.Rings 中的下标
+var ringAliases = []struct {
+	name  string
+	index int
+}{
+	{"outer", 2}, {"middle", 1}, {"inner", 0},
+	{"o", 2}, {"m", 1}, {"i", 0},
+	{"外", 2}, {"中", 1}, {"内", 0},
+}
+
+// matchRing 匹配 name 开头的圈的名称，返回圈的下标以及名称的长度，无法匹配时长度为 0
+func matchRing(name []rune, rings int) (int, int) {
+	if rings == 3 {
+		for _, alias := range ringAliases {
+			n := len([]rune(alias.name))
+			if len(name) >= n && strings.EqualFold(string(name[:n]), alias.name) {
+				return alias.index, n
+			}
+		}
+		return 0, 0
+	}
+	if index := strings.IndexRune(ringLetters(rings), unicode.ToLower(name[0])); index >= 0 {
+		return index, 1
+	}
+	return 0, 0
+}
+
+// expectedRingName 返回 rings 个圈的罗盘中期望的圈的名称，用于错误信息
+func expectedRingName(rings int) string {
+	letters := ringLetters(rings)
+	names := make([]string, 0, rings)
+	for i := rings - 1; i >= 0; i-- {
+		names = append(names, fmt.Sprintf(`"%c"`, letters[i]))
+	}
+	switch len(names) {
+	case 0:
+		return "ring name"
+	case 1:
+		return fmt.Sprintf("ring name (%s)", names[0])
+	default:
+		return fmt.Sprintf("ring name (%s or %s)", strings.Join(names[:len(names)-1], ", "), names[len(names)-1])
+	}
+}
+
+// ringGroup 解析罗盘圈转动方案表达式
+// 方案由各圈的名称组成，名称的顺序不影响结果，例如三圈罗盘中的 om 与 mo 是同一个方案；
+// 名称后可以跟随带符号的旋转速度，作用于紧邻的圈
+func (p *parser) ringGroup(rings int) (groupNode, error) {
+	node := groupNode{column: p.peek().column}
+	if p.peek().kind != tokenName {
+		return node, p.unexpected("ring group")
+	}
+	for {
+		t := p.next()
+		index := -1
+		for offset, name := 0, []rune(t.text); offset < len(name); {
+			column := t.column + offset
+			i, n := matchRing(name[offset:], rings)
+			if n == 0 {
+				return node, &ParseError{
+					Expression: p.expression,
+					Column:     column,
+					Expected:   expectedRingName(rings),
+					Found:      fmt.Sprintf(`"%s"`, string(name[offset:])),
+				}
+			}
+			if node.ringGroup.Contains(i) {
+				return node, p.errorAt(column, fmt.Errorf(`duplicated ring "%s" in ring group`, string(name[offset:offset+n])))
+			}
+			node.ringGroup |= 1 << i
+			index = i
+			offset += n
+		}
+
+		// 可选的旋转速度，没有速度时方案结束：字母连续的名称属于同一个词法单元，
+		// 名称之后紧跟另一个名称说明两者之间隔着空白字符
+		if p.peek().kind != tokenSign {
+			return node, nil
+		}
+		speed, column, err := p.signedNumber("ring group speed")
+		if err != nil {
+			return node, err
+		}
+		if speed == 0 {
+			return node, p.errorAt(column, fmt.Errorf(`speed of %s ring in ring group must not be zero`, RingName(index, rings)))
+		}
+		if node.speeds == nil {
+			node.speeds = make([]int, rings)
+			node.columns = make([]int, rings)
+		}
+		node.speeds[index] = speed
+		node.columns[index] = column
+		if p.peek().kind != tokenName {
+			return node, nil
+		}
+	}
+}
 
-	// 正则解析
-	groups := ringRegexp.FindStringSubmatch(expression)
-	if len(groups) == 0 {
-		return ring, fmt.Errorf(`invalid ring expression: "%s" (not match "%s")`, expression, ringRegexpStr)
+// buildRingGroups 汇总解析得到的方案
+// 返回方案列表以及声明了旋转速度的方案的速度，没有任何方案声明旋转速度时后者为 nil
+func (p *parser) buildRingGroups(groups []groupNode, rings int) ([]RingGroup, map[RingGroup][]int, error) {
+	ringGroups := make([]RingGroup, 0, len(groups))
+	var groupSpeeds map[RingGroup][]int
+	for _, group := range groups {
+		// 同一个方案只能出现一次，圈的顺序不同也是同一个方案
+		if containsRingGroup(ringGroups, group.ringGroup) {
+			return nil, nil, p.errorAt(group.column, fmt.Errorf(`duplicated ring group %s`, group.ringGroup.Format(rings)))
+		}
+		ringGroups = append(ringGroups, group.ringGroup)
+		if group.speeds == nil {
+			continue
+		}
+		if groupSpeeds == nil {
+			groupSpeeds = make(map[RingGroup][]int)
+		}
+		groupSpeeds[group.ringGroup] = group.speeds
+	}
+	return ringGroups, groupSpeeds, nil
+}
+
+// parseRing 解析罗盘圈表达式，例如 0+2 或 0+2>3
+func parseRing(expression string) (Ring, error) {
+	p := newParser(expression)
+	node, err := p.ring()
+	if err != nil {
+		return Ring{}, err
+	}
+	if _, err := p.expect(tokenEOF, "end of input"); err != nil {
+		return Ring{}, err
 	}
+	return node.Ring, nil
+}
 
-	locationPart := groups[ringRegexp.SubexpIndex("location")]
-	location, err := strconv.ParseInt(locationPart, 10, 8)
+// parseRingGroups 解析逗号分隔的罗盘转动方案表达式
+// 返回方案列表以及声明了旋转速度的方案的速度，没有任何方案声明旋转速度时后者为 nil
+func parseRingGroups(expression string, rings int) ([]RingGroup, map[RingGroup][]int, error) {
+	p := newParser(expression)
+	var groups []groupNode
+	bracketed, err := p.list(func() error {
+		group, err := p.ringGroup(rings)
+		if err != nil {
+			return err
+		}
+		groups = append(groups, group)
+		return nil
+	})
 	if err != nil {
-		return ring, fmt.Errorf(`parse ring location "%s" error: %w`, locationPart, err)
+		return nil, nil, err
 	}
-	ring.Location = int(location)
+	if _, err := p.expect(tokenEOF, listFollow(bracketed, "end of input")); err != nil {
+		return nil, nil, err
+	}
+	return p.buildRingGroups(groups, rings)
+}
 
-	speedPart := groups[ringRegexp.SubexpIndex("speed")]
-	speed, err := strconv.ParseInt(speedPart, 10, 8)
+// parseRingGroup 解析罗盘圈转动方案表达式，格式参见 ParseCompass
+// 返回的速度下标与 Compass.Rings 一致，没有声明任何速度时为 nil
+func parseRingGroup(expression string, rings int) (RingGroup, []int, error) {
+	p := newParser(expression)
+	node, err := p.ringGroup(rings)
 	if err != nil {
-		return ring, fmt.Errorf(`parse ring speed "%s" error: %w`, speedPart, err)
+		return 0, nil, err
 	}
-	ring.Speed = int(speed)
+	if _, err := p.expect(tokenEOF, "end of input"); err != nil {
+		return 0, nil, err
+	}
+	return node.ringGroup, node.speeds, nil
+}
 
-	return ring, nil
+// containsRingGroup 判断方案列表中是否包含指定方案
+func containsRingGroup(ringGroups []RingGroup, ringGroup RingGroup) bool {
+	for _, v := range ringGroups {
+		if v == ringGroup {
+			return true
+		}
+	}
+	return false
 }
 
-// parseRingGroups 解析罗盘转动方案表达式
-func parseRingGroups(expression string) ([]RingGroup, error) {
-	ringGroups := make([]RingGroup, 0)
-	parts := strings.Split(expression, ",")
-	for i, expr := range parts {
-		ringGroup, err := parseRingGroup(expr)
+// ParseSteps 解析解谜步骤表达式
+// 解谜步骤表达式与 Steps.Format 的结果一致，由逗号分隔的若干步组成，每一步是方案缩写与转动次数，
+// 例如经典三圈罗盘中的 mi3,om1，步骤的顺序会被保留；空字符串表示不需要任何转动。
+// 方案中可以使用与 ParseCompass 相同的别名，例如 中内3
+func ParseSteps(expression string, rings int) (Steps, error) {
+	steps := make(Steps, 0)
+	p := newParser(expression)
+	if _, ok := p.accept(tokenEOF); ok {
+		return steps, nil
+	}
+	for {
+		group, err := p.ringGroup(rings)
+		if err != nil {
+			return nil, err
+		}
+		if group.speeds != nil {
+			return nil, p.errorAt(group.column, fmt.Errorf(`unexpected speeds in step`))
+		}
+		count, _, err := p.number("step count")
 		if err != nil {
-			return nil, fmt.Errorf(`parse ring groups at index %d error: %w`, i, err)
-		}
-		ringGroups = append(ringGroups, ringGroup)
-	}
-	return ringGroups, nil
-}
-
-// parseRingGroup 解析罗盘圈转动方案表达式
-func parseRingGroup(expression string) (RingGroup, error) {
-	switch expression {
-	case "o":
-		return Outer, nil
-	case "m":
-		return Middle, nil
-	case "i":
-		return Inner, nil
-	case "om", "mo":
-		return OuterMiddle, nil
-	case "oi", "io":
-		return OuterInner, nil
-	case "mi", "im":
-		return MiddleInner, nil
-	}
-	return 0, fmt.Errorf(`unknown ring group: %s`, expression)
+			return nil, err
+		}
+		steps = append(steps, Step{RingGroup: group.ringGroup, Count: count})
+		if _, ok := p.accept(tokenComma); !ok {
+			break
+		}
+	}
+	if _, err := p.expect(tokenEOF, `"," or end of input`); err != nil {
+		return nil, err
+	}
+	return steps, nil
 }
@@ -1,9 +1,13 @@
 package ng
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
 
 func Example_parseRingGroup() {
-	rg, err := parseRingGroup("o")
+	rg, _, err := parseRingGroup("o", 3)
 	if err != nil {
 		panic(err)
 	}
@@ -13,7 +17,7 @@ func Example_parseRingGroup() {
 }
 
 func Example_parseRingGroups() {
-	rgs, err := parseRingGroups("mo,io,i")
+	rgs, _, err := parseRingGroups("mo,io,i", 3)
 	if err != nil {
 		panic(err)
 	}
@@ -30,15 +34,162 @@ func Example_parseRing() {
 	tests := []string{
 		"3+2",
 		"0-1",
+		"4+3>1",
 	}
 	for _, test := range tests {
 		ring, err := parseRing(test)
 		if err != nil {
 			panic(err)
 		}
-		fmt.Printf("location: %d, speed: %+d\n", ring.Location, ring.Speed)
+		fmt.Printf("location: %d, speed: %+d, target: %d\n", ring.Location, ring.Speed, ring.Target)
 	}
 	// Output:
-	// location: 3, speed: +2
-	// location: 0, speed: -1
+	// location: 3, speed: +2, target: 0
+	// location: 0, speed: -1, target: 0
+	// location: 4, speed: +3, target: 1
+}
+
+func Example_parseRingGroup_speeds() {
+	rg, speeds, err := parseRingGroup("m+2i-1", 3)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("%s, %v", rg.Name(), speeds)
+	// Output:
+	// MiddleInner, [-1 2 0]
+}
+
+func ExampleParseSteps() {
+	steps, err := ParseSteps("om1,mi3,om2", 3)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(len(steps), steps.String())
+	steps, err = ParseSteps("ad2,b1", 4)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(len(steps), steps.Format(4))
+	// Output:
+	// 3 mi3,om3
+	// 2 b1,ad2
+}
+
+func ExampleParseError() {
+	_, err := ParseCompass("0+2,3-3,0+3/mi,om,oi xyz")
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		fmt.Println(parseErr.Column, parseErr.Expected, parseErr.Found)
+	}
+	fmt.Println(err)
+	// Output:
+	// 22 ",", "@" or end of input "xyz"
+	// parse "0+2,3-3,0+3/mi,om,oi xyz" error at column 22: expected ",", "@" or end of input, found "xyz"
+}
+
+func TestParseCompass_Syntax(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   string
+	}{
+		{" 0+2 , 3-3 , 0+3 / mi , om , oi ", "0+2,3-3,0+3/mi,oi,om"},
+		{"[0+2,3-3,0+3]/[mi,om,oi]", "0+2,3-3,0+3/mi,oi,om"},
+		{"(0+2,3-3,0+3) / (mi,om,oi)", "0+2,3-3,0+3/mi,oi,om"},
+		{"0+2，3-3，0+3/mi，om，oi", "0+2,3-3,0+3/mi,oi,om"},
+		{"0+2,3-3,0+3/middleinner,OuterMiddle,outerInner", "0+2,3-3,0+3/mi,oi,om"},
+		{"0+2,3-3,0+3/中内,外中,外内", "0+2,3-3,0+3/mi,oi,om"},
+		{"0+2,3-3,0+3/中+2 内-1,外中,OI", "0+2,3-3,0+3/m+2i-1,oi,om"},
+		{"[0+1, 3-2, 1+3, 7+5] / [AB, dc] @ 8", "0+1,3-2,1+3,7+5/cd,ab@8"},
+	}
+	for _, test := range tests {
+		compass, err := ParseCompass(test.expression)
+		if err != nil {
+			t.Fatalf("%s: %v", test.expression, err)
+		}
+		if compass.String() != test.expected {
+			t.Errorf("%s: unexpected result: %s (expected: %s)", test.expression, compass.String(), test.expected)
+		}
+	}
+}
+
+func TestParseCompass_ParseError(t *testing.T) {
+	tests := []struct {
+		expression string
+		column     int
+		expected   string
+		found      string
+	}{
+		{"", 1, "ring location", "end of input"},
+		{"0+2,3-3,0+3", 12, `"," or "/"`, "end of input"},
+		{"0+2,3-3,0+3/mi,om,oi)", 21, `",", "@" or end of input`, `")"`},
+		{"garbage 0+2,3-3,0+3/mi,om,oi", 1, "ring location", `"garbage"`},
+		{"0+2,3-3,0+3/mi,om,oi@6 trailing", 24, "end of input", `"trailing"`},
+		{"0+2,3-3,0+3/mi,om,oi@", 22, "scales", "end of input"},
+		{"0+2,3*3,0+3/mi,om,oi", 6, `"+" or "-" before ring speed`, `"*"`},
+		{"0+2,3-3,0+/mi,om,oi", 11, "ring speed", `"/"`},
+		{"0+2,3-3,0+3>/mi,om,oi", 13, "ring target", `"/"`},
+		{"[0+2,3-3,0+3)/mi,om,oi", 13, `"," or "]"`, `")"`},
+		{"[0+2,3-3,0+3]/mi,om,oi]", 23, `",", "@" or end of input`, `"]"`},
+		{"0+2,3-3,0+3/mi,,oi", 16, "ring group", `","`},
+		{"0+2,3-3,0+3/mi,oa,oi", 17, `ring name ("o", "m" or "i")`, `"a"`},
+		{"0+2,3-3,0+3/mi,外x,oi", 17, `ring name ("o", "m" or "i")`, `"x"`},
+		{"0+1,3-2,1+3,7+5/ab,ae@8", 21, `ring name ("a", "b", "c" or "d")`, `"e"`},
+		{"0+2,3-3,0+3/m+i,om,oi", 15, "ring group speed", `"i"`},
+		{"0+2,3-3,0+3/m i,om,oi", 15, `",", "@" or end of input`, `"i"`},
+	}
+	for _, test := range tests {
+		_, err := ParseCompass(test.expression)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Fatalf("%s: expected a *ParseError, got %v", test.expression, err)
+		}
+		if parseErr.Column != test.column || parseErr.Expected != test.expected || parseErr.Found != test.found || parseErr.Err != nil {
+			t.Errorf("%s: unexpected error: %v", test.expression, err)
+		}
+	}
+}
+
+func TestParseCompass_ParseErrorColumn(t *testing.T) {
+	tests := []struct {
+		expression string
+		column     int
+	}{
+		{"6+2,3-3,0+3/mi,om,oi", 1},              // 位置超出刻度
+		{"0+6,3-3,0+3/mi,om,oi", 2},              // 速度超出刻度
+		{"0+2>6,3-3,0+3/mi,om,oi", 5},            // 目标位置超出刻度
+		{"0+1,3-2,1+3,8+5/ab,cd@8", 13},          // 位置超出自定义刻度
+		{"0+1,3-2,1+3,7+5/ab,cd@1", 23},          // 刻度过少
+		{"0+1,3-2,1+3,7+5/ab,aa@8", 21},          // 方案包含重复的圈
+		{"外内+1, 0+2,3-3/mi", 1},                  // 列号按字符计数
+		{"0+2,3-3,0+3/中内,外中外,oi", 18},            // 列号按字符计数
+		{"0+2,3-3,0+3/m+0i,om,oi", 14},           // 方案的速度为 0
+		{"0+2,3-3,0+3/m+6i,om,oi", 14},           // 方案的速度超出刻度
+		{"0+2,3-3,0+3/m+2i,om,im", 21},           // 重复的方案声明了不同的速度
+		{"0+2,3-3,0+3/mi,mi", 16},                // 重复的方案
+		{"0+2,3-3,0+3/om,oi,mo", 19},             // 圈的顺序不同也是重复的方案
+		{"0+99999999999999999999,3-3,0+3/mi", 3}, // 数值溢出
+	}
+	for _, test := range tests {
+		_, err := ParseCompass(test.expression)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Fatalf("%s: expected a *ParseError, got %v", test.expression, err)
+		}
+		if parseErr.Column != test.column {
+			t.Errorf("%s: unexpected column %d (expected: %d): %v", test.expression, parseErr.Column, test.column, err)
+		}
+	}
+}
+
+func TestParseSteps_Invalid(t *testing.T) {
+	tests := []string{"mi", "mi3,", "mi3 om1", "m+1i3", "x3", "mi-3"}
+	for _, expression := range tests {
+		if steps, err := ParseSteps(expression, 3); err == nil {
+			t.Errorf("%s: expected error, got %s", expression, steps.String())
+		}
+	}
+	steps, err := ParseSteps(" 中内3, outer1 ", 3)
+	if err != nil || steps.String() != "mi3,o1" {
+		t.Fatalf("unexpected steps: %v, %v", steps, err)
+	}
 }
@@ -0,0 +1,107 @@
+package ng
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownSolver 没有以该名称注册的求解器
+var ErrUnknownSolver = errors.New(`unknown solver`)
+
+// 未指定求解器时使用的求解器名称
+const (
+	// DefaultSolverName 默认的求解器
+	DefaultSolverName = "linear"
+	// ConstrainedSolverName 罗盘带有 Constraints 时使用的求解器，它遵守限制，参见 SolverInfo.Ordered
+	ConstrainedSolverName = "bfs"
+)
+
+// DefaultSolverFor 返回未指定求解器时求解 compass 使用的求解器名称
+func DefaultSolverFor(compass Compass) string {
+	if !compass.Constraints.IsZero() {
+		return ConstrainedSolverName
+	}
+	return DefaultSolverName
+}
+
+// SolverFactory 按选项创建求解器
+type SolverFactory func(opts SolverOptions) (Solver, error)
+
+// SolverInfo 求解器的描述信息
+type SolverInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// 罗盘有解时一定能求出解法
+	Complete bool `json:"complete"`
+	// 求出的解法是代价函数下代价最小的解法
+	Optimal bool `json:"optimal"`
+	// 支持任意圈数、刻度数、目标位置以及方案各自的旋转速度
+	Generalized bool `json:"generalized"`
+	// 求出的解法保持转动的顺序，并且遵守 Compass.Constraints
+	Ordered bool `json:"ordered"`
+}
+
+// solverEntry 注册的求解器
+type solverEntry struct {
+	info    SolverInfo
+	factory SolverFactory
+}
+
+var (
+	solversMu sync.RWMutex
+	solvers   = make(map[string]solverEntry)
+)
+
+// RegisterSolver 以 name 注册求解器，info.Name 会被设置为 name
+// 与 database/sql.Register 一样，name 为空、factory 为 nil 或者 name 重复注册时会 panic
+func RegisterSolver(name string, factory SolverFactory, info SolverInfo) {
+	solversMu.Lock()
+	defer solversMu.Unlock()
+	if name == "" {
+		panic("ng: RegisterSolver name is empty")
+	}
+	if factory == nil {
+		panic("ng: RegisterSolver factory is nil for solver " + name)
+	}
+	if _, dup := solvers[name]; dup {
+		panic("ng: RegisterSolver called twice for solver " + name)
+	}
+	info.Name = name
+	solvers[name] = solverEntry{info: info, factory: factory}
+}
+
+// NewSolver 按名称创建求解器，没有以该名称注册的求解器时返回 ErrUnknownSolver
+func NewSolver(name string, opts SolverOptions) (Solver, error) {
+	solversMu.RLock()
+	entry, ok := solvers[name]
+	solversMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf(`%w %q (must be one of %s)`, ErrUnknownSolver, name, strings.Join(SolverNames(), ", "))
+	}
+	return entry.factory(opts)
+}
+
+// Solvers 返回全部已注册求解器的描述信息，按名称排列
+func Solvers() []SolverInfo {
+	solversMu.RLock()
+	defer solversMu.RUnlock()
+	infos := make([]SolverInfo, 0, len(solvers))
+	for _, entry := range solvers {
+		infos = append(infos, entry.info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// SolverNames 返回全部已注册求解器的名称，按名称排列
+func SolverNames() []string {
+	infos := Solvers()
+	names := make([]string, len(infos))
+	for i, info := range infos {
+		names[i] = info.Name
+	}
+	return names
+}
@@ -0,0 +1,63 @@
+package ng
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func ExampleSolvers() {
+	for _, info := range Solvers() {
+		fmt.Println(info.Name, info.Complete, info.Optimal, info.Generalized, info.Ordered)
+	}
+	// Output:
+	// bfs true false true true
+	// hunger true true true false
+	// linear true true true false
+}
+
+func ExampleNewSolver() {
+	compass, err := ParseCompass("0+3,3-3,0+2/mi,om,oi")
+	if err != nil {
+		panic(err)
+	}
+	solver, err := NewSolver("linear", SolverOptions{})
+	if err != nil {
+		panic(err)
+	}
+	solution, err := solver.Solve(context.Background(), compass)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(solution.String())
+	// Output:
+	// mi3
+}
+
+func TestNewSolver_Unknown(t *testing.T) {
+	if _, err := NewSolver("magic", SolverOptions{}); !errors.Is(err, ErrUnknownSolver) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRegisterSolver_Panics(t *testing.T) {
+	tests := []struct {
+		name    string
+		factory SolverFactory
+	}{
+		{"", NewHungerSolver},
+		{"hunger", NewHungerSolver},
+		{"nil-factory", nil},
+	}
+	for _, test := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("expected RegisterSolver(%q) to panic", test.name)
+				}
+			}()
+			RegisterSolver(test.name, test.factory, SolverInfo{})
+		}()
+	}
+}
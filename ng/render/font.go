@@ -0,0 +1,111 @@
+package render
+
+import (
+	"image/color"
+	"unicode"
+)
+
+// 位图字体的字形尺寸，单位为点，字形之间留出一列空白
+const (
+	glyphWidth   = 5
+	glyphHeight  = 7
+	glyphAdvance = glyphWidth + 1
+)
+
+// glyphs 5×7 点阵字体，每个元素是一行，最高的 5 位从左到右表示各点
+// 只收录罗盘图片中会用到的字符，大写字母按小写字母绘制，其他字符绘制为方框
+var glyphs = map[rune][glyphHeight]uint8{
+	' ': {},
+	'0': {0x0e, 0x11, 0x13, 0x15, 0x19, 0x11, 0x0e},
+	'1': {0x04, 0x0c, 0x04, 0x04, 0x04, 0x04, 0x0e},
+	'2': {0x0e, 0x11, 0x01, 0x02, 0x04, 0x08, 0x1f},
+	'3': {0x1f, 0x02, 0x04, 0x02, 0x01, 0x11, 0x0e},
+	'4': {0x02, 0x06, 0x0a, 0x12, 0x1f, 0x02, 0x02},
+	'5': {0x1f, 0x10, 0x1e, 0x01, 0x01, 0x11, 0x0e},
+	'6': {0x06, 0x08, 0x10, 0x1e, 0x11, 0x11, 0x0e},
+	'7': {0x1f, 0x01, 0x02, 0x04, 0x08, 0x08, 0x08},
+	'8': {0x0e, 0x11, 0x11, 0x0e, 0x11, 0x11, 0x0e},
+	'9': {0x0e, 0x11, 0x11, 0x0f, 0x01, 0x02, 0x0c},
+	'a': {0x00, 0x00, 0x0e, 0x01, 0x0f, 0x11, 0x0f},
+	'b': {0x10, 0x10, 0x16, 0x19, 0x11, 0x11, 0x1e},
+	'c': {0x00, 0x00, 0x0e, 0x10, 0x10, 0x11, 0x0e},
+	'd': {0x01, 0x01, 0x0d, 0x13, 0x11, 0x11, 0x0f},
+	'e': {0x00, 0x00, 0x0e, 0x11, 0x1f, 0x10, 0x0e},
+	'f': {0x06, 0x09, 0x08, 0x1c, 0x08, 0x08, 0x08},
+	'g': {0x00, 0x0f, 0x11, 0x11, 0x0f, 0x01, 0x0e},
+	'h': {0x10, 0x10, 0x16, 0x19, 0x11, 0x11, 0x11},
+	'i': {0x04, 0x00, 0x0c, 0x04, 0x04, 0x04, 0x0e},
+	'j': {0x02, 0x00, 0x06, 0x02, 0x02, 0x12, 0x0c},
+	'k': {0x10, 0x10, 0x12, 0x14, 0x18, 0x14, 0x12},
+	'l': {0x0c, 0x04, 0x04, 0x04, 0x04, 0x04, 0x0e},
+	'm': {0x00, 0x00, 0x1a, 0x15, 0x15, 0x11, 0x11},
+	'n': {0x00, 0x00, 0x16, 0x19, 0x11, 0x11, 0x11},
+	'o': {0x00, 0x00, 0x0e, 0x11, 0x11, 0x11, 0x0e},
+	'p': {0x00, 0x00, 0x1e, 0x11, 0x1e, 0x10, 0x10},
+	'q': {0x00, 0x00, 0x0d, 0x13, 0x0f, 0x01, 0x01},
+	'r': {0x00, 0x00, 0x16, 0x19, 0x10, 0x10, 0x10},
+	's': {0x00, 0x00, 0x0e, 0x10, 0x0e, 0x01, 0x1e},
+	't': {0x08, 0x08, 0x1c, 0x08, 0x08, 0x09, 0x06},
+	'u': {0x00, 0x00, 0x11, 0x11, 0x11, 0x13, 0x0d},
+	'v': {0x00, 0x00, 0x11, 0x11, 0x11, 0x0a, 0x04},
+	'w': {0x00, 0x00, 0x11, 0x11, 0x15, 0x15, 0x0a},
+	'x': {0x00, 0x00, 0x11, 0x0a, 0x04, 0x0a, 0x11},
+	'y': {0x00, 0x00, 0x11, 0x11, 0x0f, 0x01, 0x0e},
+	'z': {0x00, 0x00, 0x1f, 0x02, 0x04, 0x08, 0x1f},
+	'+': {0x00, 0x04, 0x04, 0x1f, 0x04, 0x04, 0x00},
+	'-': {0x00, 0x00, 0x00, 0x1f, 0x00, 0x00, 0x00},
+	'×': {0x00, 0x11, 0x0a, 0x04, 0x0a, 0x11, 0x00},
+	'/': {0x00, 0x01, 0x02, 0x04, 0x08, 0x10, 0x00},
+	'(': {0x02, 0x04, 0x08, 0x08, 0x08, 0x04, 0x02},
+	')': {0x08, 0x04, 0x02, 0x02, 0x02, 0x04, 0x08},
+	':': {0x00, 0x0c, 0x0c, 0x00, 0x0c, 0x0c, 0x00},
+	'.': {0x00, 0x00, 0x00, 0x00, 0x00, 0x0c, 0x0c},
+	',': {0x00, 0x00, 0x00, 0x00, 0x0c, 0x04, 0x08},
+}
+
+// unknownGlyph 字体中没有收录的字符
+var unknownGlyph = [glyphHeight]uint8{0x1f, 0x11, 0x11, 0x11, 0x11, 0x11, 0x1f}
+
+// glyph 返回字符的字形
+func glyph(r rune) [glyphHeight]uint8 {
+	if g, ok := glyphs[unicode.ToLower(r)]; ok {
+		return g
+	}
+	return unknownGlyph
+}
+
+// textWidth 返回文字按 scale 倍绘制时的宽度，单位为像素，不包括最后一个字符之后的空白
+func textWidth(text string, scale int) int {
+	n := len([]rune(text))
+	if n == 0 {
+		return 0
+	}
+	return (n*glyphAdvance - 1) * scale
+}
+
+// fitScale 返回不超过 preferred 并且文字宽度不超过 width 的最大倍数，最小为 1
+func fitScale(text string, width float64, preferred int) int {
+	scale := max(preferred, 1)
+	for scale > 1 && float64(textWidth(text, scale)) > width {
+		scale--
+	}
+	return scale
+}
+
+// text 以 (centerX, top) 为上边的中点，按 scale 倍绘制文字
+func (c *canvas) text(text string, centerX, top float64, scale int, col color.RGBA) {
+	x := int(centerX) - textWidth(text, scale)/2
+	y := int(top)
+	for _, r := range text {
+		g := glyph(r)
+		for row, bits := range g {
+			for column := 0; column < glyphWidth; column++ {
+				if bits&(1<<(glyphWidth-1-column)) == 0 {
+					continue
+				}
+				c.block(x+column*scale, y+row*scale, scale, col)
+			}
+		}
+		x += glyphAdvance * scale
+	}
+}
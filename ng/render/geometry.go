@@ -0,0 +1,44 @@
+package render
+
+import (
+	"image/color"
+
+	"github.com/AyakuraYuki/go-starrail-compass/ng"
+)
+
+// Geometry 罗盘图片中各元素的位置，单位为像素，供 ng/vision 等需要在图片中定位元素的代码使用
+// 各元素的位置只与圈数和罗盘部分的边长有关，与各圈的位置、旋转速度以及方案无关
+type Geometry struct {
+	Width, Height    float64   // 图片的宽度与高度，不包括 WalkthroughPNGs 等在底部添加的说明文字
+	CenterX, CenterY float64   // 圆心
+	Radii            []float64 // 各圈的中线半径，下标与 ng.Compass.Rings 一致
+	Band             float64   // 每个圈占用的宽度
+	Knob             float64   // 指针的半径，目标位置标记的半径为 1.3 倍
+	ButtonTop        float64   // 方案按钮的上沿
+	ButtonHeight     float64   // 方案按钮的高度，按钮下方的圆点位于 0.75 倍高度处
+}
+
+// GeometryOf 返回 rings 个圈的罗盘在 opts 下的元素位置
+func GeometryOf(rings int, opts Options) Geometry {
+	l := newLayout(ng.Compass{Rings: make([]ng.Ring, rings), RingGroups: []ng.RingGroup{1}}, opts, false)
+	g := Geometry{
+		Width:        l.width,
+		Height:       l.height,
+		CenterX:      l.center.X,
+		CenterY:      l.center.Y,
+		Radii:        make([]float64, rings),
+		Band:         l.band,
+		Knob:         l.knob(),
+		ButtonTop:    l.buttons[0].Y,
+		ButtonHeight: l.buttons[0].Height,
+	}
+	for i := range g.Radii {
+		g.Radii[i] = l.ringRadius(i)
+	}
+	return g
+}
+
+// RingColor 返回下标为 index 的圈的指针颜色，方案按钮下的圆点使用同样的颜色
+func RingColor(index int) color.RGBA {
+	return ringColor(index)
+}
@@ -0,0 +1,253 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+
+	"github.com/AyakuraYuki/go-starrail-compass/ng"
+)
+
+// RenderImage 将罗盘的当前状态绘制为位图，内容与 RenderSVG 一致
+func RenderImage(compass ng.Compass, opts Options) *image.RGBA {
+	l := newLayout(compass, opts, false)
+	return l.draw(startLocations(compass), 0, "")
+}
+
+// RenderPNG 将罗盘的当前状态绘制为 PNG 图片，参见 RenderImage
+func RenderPNG(compass ng.Compass, opts Options) ([]byte, error) {
+	return encodePNG(RenderImage(compass, opts))
+}
+
+// encodePNG 将位图编码为 PNG
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf(`encode png error: %w`, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// startLocations 返回各圈的初始位置
+func startLocations(compass ng.Compass) []float64 {
+	locations := make([]float64, len(compass.Rings))
+	for i, ring := range compass.Rings {
+		locations[i] = float64(ring.Location)
+	}
+	return locations
+}
+
+// draw 绘制各圈停在 locations 时的罗盘，位置可以是小数，用于绘制转动的过程；
+// active 为正在转动的方案，对应的按钮会被高亮，caption 为底部的说明文字，需要在 newLayout 时留出位置
+func (l *layout) draw(locations []float64, active ng.RingGroup, caption string) *image.RGBA {
+	c := newCanvas(int(math.Ceil(l.width)), int(math.Ceil(l.height)), backgroundColor)
+	size := l.width
+	scale := max(1, int(math.Round(size*0.04/glyphHeight)))
+
+	for notch := 0; notch < l.scales; notch++ {
+		angle := l.angle(notch)
+		c.segment(l.polar(l.radius+l.band*0.05, angle), l.polar(l.radius+size*0.03, angle), size*0.006, inkColor)
+	}
+	for i := len(l.compass.Rings) - 1; i >= 0; i-- {
+		l.drawRing(c, i, locations[i])
+	}
+
+	text := legend(l.compass)
+	c.text(text, l.center.X, l.legendY-float64(glyphHeight*scale), fitScale(text, size*0.94, scale), inkColor)
+	for _, b := range l.buttons {
+		l.drawButton(c, b, active)
+	}
+	if caption != "" {
+		c.text(caption, l.center.X, l.caption, fitScale(caption, size*0.94, scale+1), inkColor)
+	}
+	return c.img
+}
+
+// drawRing 绘制下标为 index 的圈，与 svgWriter.ring 一致
+func (l *layout) drawRing(c *canvas, index int, location float64) {
+	ring := l.compass.Rings[index]
+	col := ringColor(index)
+	radius := l.ringRadius(index)
+	c.annulus(l.center, radius, l.band*0.8, lighten(col, 0.6))
+
+	knob := l.knob()
+	c.annulus(l.polar(radius, l.angle(ring.Target)), knob*1.3, knob*0.25, inkColor)
+
+	angle := 2 * math.Pi * location / float64(l.scales)
+	if ng.Mod(ring.Speed, l.scales) != 0 {
+		sweep := 2 * math.Pi * float64(ng.Abs(ring.Speed)) / float64(l.scales)
+		sweep = math.Min(sweep, 2*math.Pi*0.9)
+		direction := 1.0
+		if ring.Speed < 0 {
+			direction = -1
+		}
+		gap := math.Min(knob*1.6/radius, sweep/4)
+		start, end := angle+direction*gap, angle+direction*(sweep-gap)
+		width := l.band * 0.06
+		head := width * 4
+		c.arc(l.center, radius, start, direction*(sweep-2*gap-head/radius*0.5), width, inkColor)
+
+		// 箭头沿终点处的切线方向
+		tip := l.polar(radius, end)
+		dx, dy := direction*math.Sin(end), -direction*math.Cos(end)
+		base := point{X: tip.X - dx*head, Y: tip.Y - dy*head}
+		c.triangle(tip,
+			point{X: base.X - dy*head*0.5, Y: base.Y + dx*head*0.5},
+			point{X: base.X + dy*head*0.5, Y: base.Y - dx*head*0.5},
+			inkColor)
+	}
+
+	c.segment(l.polar(radius-l.band*0.4, angle), l.polar(radius+l.band*0.4, angle), knob*0.5, col)
+	c.disc(l.polar(radius, angle), knob, col)
+}
+
+// drawButton 绘制方案按钮，正在转动的方案使用深色高亮
+func (l *layout) drawButton(c *canvas, b button, active ng.RingGroup) {
+	fill, ink := buttonColor, inkColor
+	if active != 0 && active == b.RingGroup {
+		fill, ink = inkColor, buttonColor
+	}
+	border := l.width * 0.004
+	c.roundedRect(b.X, b.Y, b.Width, b.Height, b.Height*0.2, inkColor)
+	c.roundedRect(b.X+border, b.Y+border, b.Width-2*border, b.Height-2*border, b.Height*0.2-border, fill)
+
+	scale := max(1, int(math.Round(b.Height*0.32/glyphHeight)))
+	scale = fitScale(b.Label, b.Width*0.9, scale)
+	c.text(b.Label, b.X+b.Width/2, b.Y+b.Height*0.5-float64(glyphHeight*scale), scale, ink)
+	dot, y := b.Height*0.08, b.Y+b.Height*0.75
+	for k, i := range b.Rings {
+		x := b.X + b.Width/2 + (float64(k)-float64(len(b.Rings)-1)/2)*dot*3
+		c.disc(point{X: x, Y: y}, dot, ringColor(i))
+	}
+}
+
+// canvas 支持简单抗锯齿的位图画布
+type canvas struct {
+	img *image.RGBA
+}
+
+// newCanvas 创建以 background 填充的画布
+func newCanvas(width, height int, background color.RGBA) *canvas {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = background.R, background.G, background.B, background.A
+	}
+	return &canvas{img: img}
+}
+
+// paint 在 [x0, x1] × [y0, y1] 范围内按像素中心到图形边界的有向距离填充颜色，
+// 距离小于 0 表示在图形内部，边界附近一个像素内按距离混合颜色
+func (c *canvas) paint(x0, y0, x1, y1 float64, col color.RGBA, distance func(p point) float64) {
+	bounds := c.img.Bounds()
+	minX, minY := max(int(math.Floor(x0))-1, bounds.Min.X), max(int(math.Floor(y0))-1, bounds.Min.Y)
+	maxX, maxY := min(int(math.Ceil(x1))+1, bounds.Max.X-1), min(int(math.Ceil(y1))+1, bounds.Max.Y-1)
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			alpha := math.Max(0, math.Min(1, 0.5-distance(point{X: float64(x) + 0.5, Y: float64(y) + 0.5})))
+			if alpha > 0 {
+				c.blend(x, y, col, alpha)
+			}
+		}
+	}
+}
+
+// blend 将颜色按 alpha 混合到像素上
+func (c *canvas) blend(x, y int, col color.RGBA, alpha float64) {
+	i := c.img.PixOffset(x, y)
+	mix := func(dst, src uint8) uint8 {
+		return uint8(math.Round(float64(dst)*(1-alpha) + float64(src)*alpha))
+	}
+	c.img.Pix[i] = mix(c.img.Pix[i], col.R)
+	c.img.Pix[i+1] = mix(c.img.Pix[i+1], col.G)
+	c.img.Pix[i+2] = mix(c.img.Pix[i+2], col.B)
+}
+
+// block 填充左上角为 (x, y)、边长为 size 的正方形，不做抗锯齿，用于绘制文字
+func (c *canvas) block(x, y, size int, col color.RGBA) {
+	r := image.Rect(x, y, x+size, y+size).Intersect(c.img.Bounds())
+	for py := r.Min.Y; py < r.Max.Y; py++ {
+		for px := r.Min.X; px < r.Max.X; px++ {
+			c.img.SetRGBA(px, py, col)
+		}
+	}
+}
+
+// disc 填充圆
+func (c *canvas) disc(center point, radius float64, col color.RGBA) {
+	c.paint(center.X-radius, center.Y-radius, center.X+radius, center.Y+radius, col, func(p point) float64 {
+		return math.Hypot(p.X-center.X, p.Y-center.Y) - radius
+	})
+}
+
+// annulus 绘制中线半径为 radius、宽度为 width 的圆环
+func (c *canvas) annulus(center point, radius, width float64, col color.RGBA) {
+	outer := radius + width/2
+	c.paint(center.X-outer, center.Y-outer, center.X+outer, center.Y+outer, col, func(p point) float64 {
+		return math.Abs(math.Hypot(p.X-center.X, p.Y-center.Y)-radius) - width/2
+	})
+}
+
+// segment 绘制宽度为 width 的线段
+func (c *canvas) segment(a, b point, width float64, col color.RGBA) {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	length := dx*dx + dy*dy
+	c.paint(math.Min(a.X, b.X)-width, math.Min(a.Y, b.Y)-width, math.Max(a.X, b.X)+width, math.Max(a.Y, b.Y)+width, col, func(p point) float64 {
+		t := 0.0
+		if length > 0 {
+			t = math.Max(0, math.Min(1, ((p.X-a.X)*dx+(p.Y-a.Y)*dy)/length))
+		}
+		return math.Hypot(p.X-a.X-t*dx, p.Y-a.Y-t*dy) - width/2
+	})
+}
+
+// arc 绘制从角度 start 开始、转过 sweep 的圆弧，角度的方向与 layout.angle 一致，sweep 为负数时逆时针
+func (c *canvas) arc(center point, radius, start, sweep, width float64, col color.RGBA) {
+	if sweep < 0 {
+		start, sweep = start+sweep, -sweep
+	}
+	end := start + sweep
+	from, to := polarPoint(center, radius, start), polarPoint(center, radius, end)
+	outer := radius + width
+	c.paint(center.X-outer, center.Y-outer, center.X+outer, center.Y+outer, col, func(p point) float64 {
+		angle := math.Atan2(center.Y-p.Y, center.X-p.X)
+		if math.Mod(angle-start+4*math.Pi, 2*math.Pi) <= sweep {
+			return math.Abs(math.Hypot(p.X-center.X, p.Y-center.Y)-radius) - width/2
+		}
+		return math.Min(math.Hypot(p.X-from.X, p.Y-from.Y), math.Hypot(p.X-to.X, p.Y-to.Y)) - width/2
+	})
+}
+
+// triangle 填充三角形
+func (c *canvas) triangle(a, b, d point, col color.RGBA) {
+	vertices := []point{a, b, d}
+	// 统一为顺时针（图片坐标系中 y 轴向下）排列，使各边的外法线指向三角形外侧
+	if (b.X-a.X)*(d.Y-a.Y)-(b.Y-a.Y)*(d.X-a.X) < 0 {
+		vertices[1], vertices[2] = d, b
+	}
+	c.paint(math.Min(a.X, math.Min(b.X, d.X)), math.Min(a.Y, math.Min(b.Y, d.Y)), math.Max(a.X, math.Max(b.X, d.X)), math.Max(a.Y, math.Max(b.Y, d.Y)), col, func(p point) float64 {
+		distance := math.Inf(-1)
+		for i, v := range vertices {
+			w := vertices[(i+1)%len(vertices)]
+			ex, ey := w.X-v.X, w.Y-v.Y
+			length := math.Hypot(ex, ey)
+			if length == 0 {
+				continue
+			}
+			distance = math.Max(distance, ((p.X-v.X)*ey-(p.Y-v.Y)*ex)/length)
+		}
+		return distance
+	})
+}
+
+// roundedRect 填充圆角矩形
+func (c *canvas) roundedRect(x, y, width, height, radius float64, col color.RGBA) {
+	cx, cy := x+width/2, y+height/2
+	c.paint(x, y, x+width, y+height, col, func(p point) float64 {
+		qx := math.Abs(p.X-cx) - width/2 + radius
+		qy := math.Abs(p.Y-cy) - height/2 + radius
+		return math.Hypot(math.Max(qx, 0), math.Max(qy, 0)) + math.Min(math.Max(qx, qy), 0) - radius
+	})
+}
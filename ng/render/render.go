@@ -0,0 +1,180 @@
+// Package render 将引航罗盘绘制为图片，用于攻略页面等需要直观展示罗盘状态的场合
+//
+// 罗盘的各圈绘制为同心圆环，从内到外与 ng.Compass.Rings 一致；与 ng.Ring.Location 一致，刻度 0 位于正左方，顺时针为正。
+// 每个圈上的实心指针表示当前位置，空心标记表示目标位置，指针旁的弧形箭头表示转动一次的方向与角度；
+// 图片下方依次列出各圈的旋转速度以及可以使用的方案按钮，按钮下的圆点表示方案转动的圈。
+//
+// RenderSVG 输出矢量图；RenderImage 与 RenderPNG 输出内容相同的位图，文字使用内置的点阵字体；
+// WalkthroughPNGs 与 WalkthroughGIF 将 ng.Simulate 记录的解谜过程逐帧绘制，便于玩家照着解法操作
+package render
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strings"
+
+	"github.com/AyakuraYuki/go-starrail-compass/ng"
+)
+
+// DefaultSize Options.Size 为 0 时使用的罗盘部分的边长，单位为像素
+const DefaultSize = 360
+
+// Options 绘制选项
+type Options struct {
+	// 罗盘部分的边长，单位为像素，0 表示 DefaultSize；图片的高度还包括下方的说明与方案按钮
+	Size int
+	// 图片的标题，为空时不输出
+	Title string
+}
+
+// palette 各圈的颜色，下标与 ng.Compass.Rings 一致，圈数更多时循环使用
+var palette = []color.RGBA{
+	{R: 0xd9, G: 0x8c, B: 0x3f, A: 0xff},
+	{R: 0x3f, G: 0x8c, B: 0xd9, A: 0xff},
+	{R: 0x5a, G: 0xa8, B: 0x6b, A: 0xff},
+	{R: 0xa8, G: 0x5a, B: 0xc0, A: 0xff},
+	{R: 0xc0, G: 0x50, B: 0x5a, A: 0xff},
+	{R: 0x4f, G: 0xa8, B: 0xa8, A: 0xff},
+	{R: 0x8c, G: 0x7a, B: 0x3f, A: 0xff},
+	{R: 0x6b, G: 0x6b, B: 0x8c, A: 0xff},
+}
+
+// 其他元素的颜色
+var (
+	backgroundColor = color.RGBA{R: 0xfb, G: 0xf8, B: 0xf1, A: 0xff}
+	inkColor        = color.RGBA{R: 0x33, G: 0x33, B: 0x33, A: 0xff}
+	buttonColor     = color.RGBA{R: 0xee, G: 0xe8, B: 0xda, A: 0xff}
+)
+
+// ringColor 返回下标为 index 的圈的颜色
+func ringColor(index int) color.RGBA {
+	return palette[index%len(palette)]
+}
+
+// lighten 将颜色与白色按 ratio 混合
+func lighten(c color.RGBA, ratio float64) color.RGBA {
+	mix := func(v uint8) uint8 {
+		return uint8(math.Round(float64(v) + (255-float64(v))*ratio))
+	}
+	return color.RGBA{R: mix(c.R), G: mix(c.G), B: mix(c.B), A: c.A}
+}
+
+// point 图片中的坐标
+type point struct {
+	X, Y float64
+}
+
+// button 方案按钮
+type button struct {
+	X, Y, Width, Height float64
+	RingGroup           ng.RingGroup
+	Label               string
+	Rings               []int // 方案转动的圈的下标，与方案缩写一样从外到内排列
+}
+
+// layout 罗盘各元素的位置，SVG 与位图共用
+type layout struct {
+	compass ng.Compass
+	scales  int
+	width   float64
+	height  float64
+	center  point
+	radius  float64 // 最外侧的圈的外沿半径
+	band    float64 // 每个圈占用的宽度
+	legendY float64 // 旋转速度说明的基线
+	buttons []button
+	caption float64 // 位图底部说明文字的顶端，没有说明文字时为 0
+}
+
+// newLayout 计算罗盘各元素的位置，caption 为 true 时在方案按钮下方留出一行说明文字
+func newLayout(compass ng.Compass, opts Options, caption bool) *layout {
+	size := float64(opts.Size)
+	if opts.Size <= 0 {
+		size = DefaultSize
+	}
+	l := &layout{compass: compass, scales: compass.ScaleCount(), width: size}
+	margin := size * 0.08
+	l.center = point{X: size / 2, Y: size / 2}
+	l.radius = size/2 - margin
+	// 圆心处留出半个圈的宽度，避免内圈过小导致指针遮住箭头
+	if len(compass.Rings) > 0 {
+		l.band = l.radius / (float64(len(compass.Rings)) + 0.5)
+	}
+	l.legendY = size + size*0.02
+
+	// 方案按钮排成一行，按钮之间留出间隔
+	top := l.legendY + size*0.05
+	height := size * 0.14
+	gap := size * 0.03
+	if count := len(compass.RingGroups); count > 0 {
+		width := (size - 2*gap - gap*float64(count-1)) / float64(count)
+		for j, rg := range compass.RingGroups {
+			b := button{X: gap + float64(j)*(width+gap), Y: top, Width: width, Height: height, RingGroup: rg, Label: groupLabel(compass, rg)}
+			for i := len(compass.Rings) - 1; i >= 0; i-- {
+				if rg.Contains(i) {
+					b.Rings = append(b.Rings, i)
+				}
+			}
+			l.buttons = append(l.buttons, b)
+		}
+	}
+	l.height = top + height + gap
+	if caption {
+		l.caption = l.height
+		l.height += size * 0.08
+	}
+	return l
+}
+
+// ringRadius 返回下标为 index 的圈的中线半径
+func (l *layout) ringRadius(index int) float64 {
+	return l.band * (float64(index) + 1)
+}
+
+// knob 返回指针的半径
+func (l *layout) knob() float64 {
+	return l.band * 0.2
+}
+
+// angle 返回刻度对应的角度，单位为弧度，正左方为 0，顺时针为正
+func (l *layout) angle(notch int) float64 {
+	return 2 * math.Pi * float64(ng.Mod(notch, l.scales)) / float64(l.scales)
+}
+
+// polar 返回与圆心距离为 radius、角度为 angle 的点
+func (l *layout) polar(radius, angle float64) point {
+	return polarPoint(l.center, radius, angle)
+}
+
+// polarPoint 返回与 center 距离为 radius、角度为 angle 的点，角度的方向与 layout.angle 一致
+// 图片坐标系中 y 轴向下，从正左方顺时针转过 90° 是正上方
+func polarPoint(center point, radius, angle float64) point {
+	return point{X: center.X - radius*math.Cos(angle), Y: center.Y - radius*math.Sin(angle)}
+}
+
+// legend 返回各圈旋转速度的说明，从外到内排列，例如 outer +3  middle -3  inner +2
+func legend(compass ng.Compass) string {
+	rings := len(compass.Rings)
+	parts := make([]string, 0, rings)
+	for i := rings - 1; i >= 0; i-- {
+		parts = append(parts, fmt.Sprintf("%s %+d", ng.RingName(i, rings), compass.Rings[i].Speed))
+	}
+	return strings.Join(parts, "  ")
+}
+
+// groupLabel 返回方案按钮上的文字，方案声明了旋转速度时附带各圈转动的刻度，例如 m+2i-1
+func groupLabel(compass ng.Compass, rg ng.RingGroup) string {
+	rings := len(compass.Rings)
+	if _, ok := compass.GroupSpeeds[rg]; !ok {
+		return rg.Format(rings)
+	}
+	var sb strings.Builder
+	movement := compass.Movement(rg)
+	for i := rings - 1; i >= 0; i-- {
+		if rg.Contains(i) {
+			fmt.Fprintf(&sb, "%s%+d", ng.RingGroup(1<<i).Format(rings), movement[i])
+		}
+	}
+	return sb.String()
+}
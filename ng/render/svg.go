@@ -0,0 +1,133 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"math"
+	"strings"
+
+	"github.com/AyakuraYuki/go-starrail-compass/ng"
+)
+
+// RenderSVG 将罗盘的当前状态绘制为 SVG 图片
+// 输出只与罗盘和选项有关，坐标保留两位小数，相同的输入总是得到相同的字节
+func RenderSVG(compass ng.Compass, opts Options) []byte {
+	l := newLayout(compass, opts, false)
+	w := &svgWriter{}
+	w.printf(`<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s" font-family="sans-serif">`+"\n",
+		num(l.width), num(l.height), num(l.width), num(l.height))
+	if opts.Title != "" {
+		w.printf("<title>%s</title>\n", escape(opts.Title))
+	}
+	w.printf(`<defs><marker id="arrow" viewBox="0 0 10 10" refX="8" refY="5" markerWidth="4" markerHeight="4" orient="auto-start-reverse"><path d="M0,0 L10,5 L0,10 z" fill="%s"/></marker></defs>`+"\n", hex(inkColor))
+	w.printf(`<rect width="%s" height="%s" fill="%s"/>`+"\n", num(l.width), num(l.height), hex(backgroundColor))
+
+	// 刻度
+	for notch := 0; notch < l.scales; notch++ {
+		angle := l.angle(notch)
+		from, to := l.polar(l.radius+l.band*0.05, angle), l.polar(l.radius+l.width*0.03, angle)
+		w.printf(`<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s" stroke-width="%s"/>`+"\n",
+			num(from.X), num(from.Y), num(to.X), num(to.Y), hex(inkColor), num(l.width*0.006))
+	}
+
+	// 从外到内绘制各圈，内圈覆盖在外圈之上
+	for i := len(compass.Rings) - 1; i >= 0; i-- {
+		w.ring(l, i)
+	}
+
+	w.printf(`<text x="%s" y="%s" font-size="%s" text-anchor="middle" fill="%s">%s</text>`+"\n",
+		num(l.center.X), num(l.legendY), num(l.width*0.04), hex(inkColor), escape(legend(compass)))
+	for _, b := range l.buttons {
+		w.button(l, b)
+	}
+	w.printf("</svg>\n")
+	return w.buf.Bytes()
+}
+
+// svgWriter 输出 SVG 元素
+type svgWriter struct {
+	buf bytes.Buffer
+}
+
+// printf 按格式输出
+func (w *svgWriter) printf(format string, args ...any) {
+	fmt.Fprintf(&w.buf, format, args...)
+}
+
+// ring 绘制下标为 index 的圈：圆环、目标位置标记、转动方向箭头以及当前位置指针
+func (w *svgWriter) ring(l *layout, index int) {
+	ring := l.compass.Rings[index]
+	c := ringColor(index)
+	radius := l.ringRadius(index)
+	w.printf(`<circle cx="%s" cy="%s" r="%s" fill="none" stroke="%s" stroke-width="%s"/>`+"\n",
+		num(l.center.X), num(l.center.Y), num(radius), hex(lighten(c, 0.6)), num(l.band*0.8))
+
+	knob := l.knob()
+	target := l.polar(radius, l.angle(ring.Target))
+	w.printf(`<circle cx="%s" cy="%s" r="%s" fill="none" stroke="%s" stroke-width="%s" stroke-dasharray="%s"/>`+"\n",
+		num(target.X), num(target.Y), num(knob*1.3), hex(inkColor), num(knob*0.25), num(knob*0.4))
+
+	// 箭头从指针出发，沿转动方向经过一次转动的角度，与指针和终点各留出一些间隔
+	if ng.Mod(ring.Speed, l.scales) != 0 {
+		sweep := 2 * math.Pi * float64(ng.Abs(ring.Speed)) / float64(l.scales)
+		sweep = math.Min(sweep, 2*math.Pi*0.9)
+		direction := 1.0
+		if ring.Speed < 0 {
+			direction = -1
+		}
+		gap := math.Min(knob*1.6/radius, sweep/4)
+		start := l.angle(ring.Location) + direction*gap
+		end := l.angle(ring.Location) + direction*(sweep-gap)
+		from, to := l.polar(radius, start), l.polar(radius, end)
+		large, clockwise := 0, 1
+		if sweep-2*gap > math.Pi {
+			large = 1
+		}
+		if direction < 0 {
+			clockwise = 0
+		}
+		w.printf(`<path d="M%s,%s A%s,%s 0 %d %d %s,%s" fill="none" stroke="%s" stroke-width="%s" marker-end="url(#arrow)"/>`+"\n",
+			num(from.X), num(from.Y), num(radius), num(radius), large, clockwise, num(to.X), num(to.Y), hex(inkColor), num(l.band*0.06))
+	}
+
+	pointer := l.polar(radius, l.angle(ring.Location))
+	inner, outer := l.polar(radius-l.band*0.4, l.angle(ring.Location)), l.polar(radius+l.band*0.4, l.angle(ring.Location))
+	w.printf(`<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s" stroke-width="%s"/>`+"\n",
+		num(inner.X), num(inner.Y), num(outer.X), num(outer.Y), hex(c), num(knob*0.5))
+	w.printf(`<circle cx="%s" cy="%s" r="%s" fill="%s"/>`+"\n", num(pointer.X), num(pointer.Y), num(knob), hex(c))
+}
+
+// button 绘制方案按钮，按钮下方的圆点表示方案转动的圈
+func (w *svgWriter) button(l *layout, b button) {
+	w.printf(`<rect x="%s" y="%s" width="%s" height="%s" rx="%s" fill="%s" stroke="%s" stroke-width="%s"/>`+"\n",
+		num(b.X), num(b.Y), num(b.Width), num(b.Height), num(b.Height*0.2), hex(buttonColor), hex(inkColor), num(l.width*0.004))
+	w.printf(`<text x="%s" y="%s" font-size="%s" text-anchor="middle" fill="%s">%s</text>`+"\n",
+		num(b.X+b.Width/2), num(b.Y+b.Height*0.5), num(b.Height*0.32), hex(inkColor), escape(b.Label))
+	dot, y := b.Height*0.08, b.Y+b.Height*0.75
+	for k, i := range b.Rings {
+		x := b.X + b.Width/2 + (float64(k)-float64(len(b.Rings)-1)/2)*dot*3
+		w.printf(`<circle cx="%s" cy="%s" r="%s" fill="%s"/>`+"\n", num(x), num(y), num(dot), hex(ringColor(i)))
+	}
+}
+
+// num 格式化坐标，保留两位小数并去掉多余的 0
+func num(v float64) string {
+	if math.Abs(v) < 0.005 {
+		return "0"
+	}
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", v), "0"), ".")
+}
+
+// hex 返回颜色的 #rrggbb 表述
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// escape 转义 XML 文本
+func escape(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
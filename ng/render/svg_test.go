@@ -0,0 +1,84 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AyakuraYuki/go-starrail-compass/ng"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+func ExampleRenderSVG() {
+	compass, err := ng.ParseCompass("0+3,3-3,0+2/mi,om,oi")
+	if err != nil {
+		panic(err)
+	}
+	svg := RenderSVG(compass, Options{Size: 240})
+	fmt.Printf("%s\n", bytes.SplitN(svg, []byte("\n"), 2)[0])
+	// Output:
+	// <svg xmlns="http://www.w3.org/2000/svg" width="240" height="297.6" viewBox="0 0 240 297.6" font-family="sans-serif">
+}
+
+// TestRenderSVG_Golden 检查 RenderSVG 的输出与 testdata 中的文件一致，并且是格式正确的 XML
+// 使用 go test -update 重新生成这些文件
+func TestRenderSVG_Golden(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		opts       Options
+	}{
+		{"readme-defect", "0+3,3-3,0+2/mi,om,oi", Options{Title: "0+3,3-3,0+2/mi,om,oi"}},
+		{"solved", "0+2,0-3,0+3/mi,om,oi", Options{}},
+		{"target", "0+2>3,3-3,1+3>5/mi,om,oi", Options{Size: 240}},
+		{"group-speeds", "0+2,3-3,0+3/m+2i-1,om,o", Options{}},
+		{"generalized", "5+1>2,6-2,7+3>7,4+5/ab,dc,ad,b@8", Options{Title: "<four rings & 8 scales>"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			compass, err := ng.ParseCompass(test.expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			svg := RenderSVG(compass, test.opts)
+			if err := checkXML(svg); err != nil {
+				t.Fatalf("malformed svg: %v", err)
+			}
+			if !bytes.Equal(RenderSVG(compass, test.opts), svg) {
+				t.Fatal("output is not deterministic")
+			}
+
+			path := filepath.Join("testdata", test.name+".svg")
+			if *update {
+				if err := os.WriteFile(path, svg, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			expected, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(svg, expected) {
+				t.Fatalf("output differs from %s, run go test -update to regenerate it:\n%s", path, svg)
+			}
+		})
+	}
+}
+
+// checkXML 检查 data 是格式正确的 XML
+func checkXML(data []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		if _, err := decoder.Token(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"sort"
+
+	"github.com/AyakuraYuki/go-starrail-compass/ng"
+)
+
+// 动画的默认选项
+const (
+	DefaultTweens = 4   // 每次转动插入的过渡帧数量
+	DefaultDelay  = 100 // 每个状态停留的时间，单位为 1/100 秒
+	tweenDelay    = 6   // 过渡帧停留的时间，单位为 1/100 秒
+)
+
+// WalkthroughOptions 解法演示动画的选项
+type WalkthroughOptions struct {
+	Options
+	// 每次转动在前后两个状态之间插入的过渡帧数量，0 表示 DefaultTweens，负数表示不插入过渡帧
+	Tweens int
+	// 每个状态停留的时间，单位为 1/100 秒，0 表示 DefaultDelay；最后一个状态停留的时间加倍
+	Delay int
+}
+
+// WalkthroughPNGs 将 ng.Simulate 记录的每个状态分别绘制为 PNG 图片，下标与 Trace.Frames 一致
+// 每张图片底部的说明文字参见 FrameLabels，本次转动的方案按钮会被高亮
+func WalkthroughPNGs(trace ng.Trace, opts Options) ([][]byte, error) {
+	if len(trace.Frames) == 0 {
+		return nil, fmt.Errorf(`trace has no frames`)
+	}
+	l := newLayout(trace.Compass, opts, true)
+	labels := FrameLabels(trace)
+	images := make([][]byte, len(trace.Frames))
+	for i, frame := range trace.Frames {
+		data, err := encodePNG(l.draw(frameLocations(frame.Locations), frame.RingGroup, labels[i]))
+		if err != nil {
+			return nil, err
+		}
+		images[i] = data
+	}
+	return images, nil
+}
+
+// WalkthroughGIF 将 ng.Simulate 记录的解谜过程绘制为循环播放的 GIF 动画
+// 每次转动时被转动的圈从前一个状态逐渐转到下一个状态，各帧共用一个调色板
+func WalkthroughGIF(trace ng.Trace, opts WalkthroughOptions) ([]byte, error) {
+	if len(trace.Frames) == 0 {
+		return nil, fmt.Errorf(`trace has no frames`)
+	}
+	tweens := opts.Tweens
+	if tweens == 0 {
+		tweens = DefaultTweens
+	}
+	tweens = max(tweens, 0)
+	delay := opts.Delay
+	if delay <= 0 {
+		delay = DefaultDelay
+	}
+
+	l := newLayout(trace.Compass, opts.Options, true)
+	labels := FrameLabels(trace)
+	var frames []*image.RGBA
+	var delays []int
+	for i, frame := range trace.Frames {
+		if i > 0 {
+			// 过渡帧按被转动的圈转过的刻度插值，转动方向与旋转速度一致
+			previous := frameLocations(trace.Frames[i-1].Locations)
+			movement := trace.Compass.Movement(frame.RingGroup)
+			for k := 1; k <= tweens; k++ {
+				t := float64(k) / float64(tweens+1)
+				locations := make([]float64, len(previous))
+				for r := range previous {
+					locations[r] = previous[r] + float64(movement[r])*t
+				}
+				frames = append(frames, l.draw(locations, frame.RingGroup, labels[i]))
+				delays = append(delays, tweenDelay)
+			}
+		}
+		frames = append(frames, l.draw(frameLocations(frame.Locations), frame.RingGroup, labels[i]))
+		delays = append(delays, delay)
+	}
+	delays[len(delays)-1] *= 2
+
+	p := quantize(frames)
+	animation := &gif.GIF{Delay: delays}
+	for _, frame := range frames {
+		paletted := image.NewPaletted(frame.Bounds(), p)
+		for y := frame.Rect.Min.Y; y < frame.Rect.Max.Y; y++ {
+			for x := frame.Rect.Min.X; x < frame.Rect.Max.X; x++ {
+				paletted.SetColorIndex(x, y, uint8(p.Index(frame.RGBAAt(x, y))))
+			}
+		}
+		animation.Image = append(animation.Image, paletted)
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, animation); err != nil {
+		return nil, fmt.Errorf(`encode gif error: %w`, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// FrameLabels 返回 Trace.Frames 中每个状态的说明文字，例如 start、mi ×3 (2/3)
+// 连续转动同一个方案视为一步，只转动一次时省略进度；最后一个状态完成谜题时附加 solved
+func FrameLabels(trace ng.Trace) []string {
+	rings := len(trace.Compass.Rings)
+	labels := make([]string, len(trace.Frames))
+	for i := 0; i < len(trace.Frames); {
+		frame := trace.Frames[i]
+		if frame.Press == 0 {
+			labels[i] = "start"
+			i++
+			continue
+		}
+		count := 1
+		for i+count < len(trace.Frames) && trace.Frames[i+count].RingGroup == frame.RingGroup {
+			count++
+		}
+		for n := 0; n < count; n++ {
+			labels[i+n] = fmt.Sprintf("%s ×%d", frame.RingGroup.Format(rings), count)
+			if count > 1 {
+				labels[i+n] += fmt.Sprintf(" (%d/%d)", n+1, count)
+			}
+		}
+		i += count
+	}
+	if len(labels) > 0 && trace.Solved() {
+		labels[len(labels)-1] += " solved"
+	}
+	return labels
+}
+
+// frameLocations 将整数位置转换为 layout.draw 使用的位置
+func frameLocations(locations []int) []float64 {
+	converted := make([]float64, len(locations))
+	for i, location := range locations {
+		converted[i] = float64(location)
+	}
+	return converted
+}
+
+// quantize 统计各帧中出现的颜色，选取出现次数最多的至多 256 种颜色作为 GIF 的调色板
+// 图片中的大部分像素是少数几种纯色，抗锯齿产生的其他颜色使用调色板中最接近的颜色
+func quantize(frames []*image.RGBA) color.Palette {
+	counts := make(map[color.RGBA]int)
+	for _, frame := range frames {
+		for i := 0; i < len(frame.Pix); i += 4 {
+			counts[color.RGBA{R: frame.Pix[i], G: frame.Pix[i+1], B: frame.Pix[i+2], A: frame.Pix[i+3]}]++
+		}
+	}
+	colors := make([]color.RGBA, 0, len(counts))
+	for c := range counts {
+		colors = append(colors, c)
+	}
+	sort.Slice(colors, func(i, j int) bool {
+		if counts[colors[i]] != counts[colors[j]] {
+			return counts[colors[i]] > counts[colors[j]]
+		}
+		a, b := colors[i], colors[j]
+		return uint32(a.R)<<24|uint32(a.G)<<16|uint32(a.B)<<8|uint32(a.A) < uint32(b.R)<<24|uint32(b.G)<<16|uint32(b.B)<<8|uint32(b.A)
+	})
+	p := make(color.Palette, 0, 256)
+	for _, c := range colors[:min(len(colors), 256)] {
+		p = append(p, c)
+	}
+	return p
+}
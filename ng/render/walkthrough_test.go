@@ -0,0 +1,193 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/png"
+	"math"
+	"testing"
+
+	"github.com/AyakuraYuki/go-starrail-compass/ng"
+)
+
+func ExampleFrameLabels() {
+	compass, err := ng.ParseCompass("0+3,3-3,0+2/mi,om,oi")
+	if err != nil {
+		panic(err)
+	}
+	trace, err := ng.Simulate(compass, ng.Steps{{RingGroup: ng.OuterMiddle, Count: 1}, {RingGroup: ng.MiddleInner, Count: 3}, {RingGroup: ng.OuterMiddle, Count: 5}})
+	if err != nil {
+		panic(err)
+	}
+	for _, label := range FrameLabels(trace) {
+		fmt.Println(label)
+	}
+	// Output:
+	// start
+	// om ×1
+	// mi ×3 (1/3)
+	// mi ×3 (2/3)
+	// mi ×3 (3/3)
+	// om ×5 (1/5)
+	// om ×5 (2/5)
+	// om ×5 (3/5)
+	// om ×5 (4/5)
+	// om ×5 (5/5) solved
+}
+
+func TestRenderPNG(t *testing.T) {
+	compass, err := ng.ParseCompass("5+1>2,6-2,7+3>7,4+5/ab,dc,ad,b@8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := RenderPNG(compass, Options{Size: 200})
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := newLayout(compass, Options{Size: 200}, false)
+	if img.Bounds() != image.Rect(0, 0, 200, int(l.height+0.5)) {
+		t.Fatalf("unexpected bounds %v", img.Bounds())
+	}
+	// 各圈的指针使用圈的颜色
+	for i, ring := range compass.Rings {
+		p := l.polar(l.ringRadius(i), l.angle(ring.Location))
+		if got := img.At(int(p.X), int(p.Y)); got != ringColor(i) {
+			t.Errorf("ring %d: unexpected pointer color %v", i, got)
+		}
+	}
+}
+
+// TestRenderImage_Orientation 检查刻度的方向与 ng.Ring.Location 一致：刻度 0 位于正左方，顺时针为正
+func TestRenderImage_Orientation(t *testing.T) {
+	// 外圈在刻度 1（左上方 ∠60°），中圈在刻度 3（正右方），内圈在刻度 0（正左方）
+	compass, err := ng.ParseCompass("1+1,3+1,0+1/o,m,i")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := RenderImage(compass, Options{Size: 300})
+	g := GeometryOf(3, Options{Size: 300})
+	tests := []struct {
+		index int
+		x, y  float64
+	}{
+		{ng.InnerRing, g.CenterX - g.Radii[ng.InnerRing], g.CenterY},
+		{ng.MiddleRing, g.CenterX + g.Radii[ng.MiddleRing], g.CenterY},
+		{ng.OuterRing, g.CenterX - g.Radii[ng.OuterRing]*0.5, g.CenterY - g.Radii[ng.OuterRing]*math.Sqrt(3)/2},
+	}
+	for _, test := range tests {
+		if got := img.At(int(test.x), int(test.y)); got != RingColor(test.index) {
+			t.Errorf("ring %d: expected the pointer at (%.0f, %.0f), got color %v", test.index, test.x, test.y, got)
+		}
+	}
+}
+
+func TestWalkthroughPNGs(t *testing.T) {
+	compass, err := ng.ParseCompass("0+3,3-3,0+2/mi,om,oi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trace, err := ng.Simulate(compass, ng.Steps{{RingGroup: ng.MiddleInner, Count: 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	images, err := WalkthroughPNGs(trace, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(images) != len(trace.Frames) {
+		t.Fatalf("expected %d images, got %d", len(trace.Frames), len(images))
+	}
+
+	l := newLayout(compass, Options{}, true)
+	for i, data := range images {
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for r, location := range trace.Frames[i].Locations {
+			p := l.polar(l.ringRadius(r), l.angle(location))
+			if got := img.At(int(p.X), int(p.Y)); got != ringColor(r) {
+				t.Errorf("frame %d, ring %d: unexpected pointer color %v", i, r, got)
+			}
+		}
+		// 转动 mi 时第一个按钮被高亮，取按钮左侧没有文字的位置检查填充色
+		b := l.buttons[0]
+		expected := buttonColor
+		if i > 0 {
+			expected = inkColor
+		}
+		if got := img.At(int(b.X+b.Width*0.1), int(b.Y+b.Height*0.5)); got != expected {
+			t.Errorf("frame %d: unexpected button color %v", i, got)
+		}
+	}
+
+	if _, err := WalkthroughPNGs(ng.Trace{Compass: compass}, Options{}); err == nil {
+		t.Fatal("expected error for an empty trace")
+	}
+}
+
+func TestWalkthroughGIF(t *testing.T) {
+	compass, err := ng.ParseCompass("0+3,3-3,0+2/mi,om,oi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trace, err := ng.Simulate(compass, ng.Steps{{RingGroup: ng.OuterMiddle, Count: 1}, {RingGroup: ng.MiddleInner, Count: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		opts   WalkthroughOptions
+		frames int
+		delay  int
+	}{
+		{WalkthroughOptions{Options: Options{Size: 160}}, 1 + 3*(DefaultTweens+1), DefaultDelay},
+		{WalkthroughOptions{Options: Options{Size: 160}, Tweens: 2, Delay: 50}, 1 + 3*3, 50},
+		{WalkthroughOptions{Options: Options{Size: 160}, Tweens: -1}, 4, DefaultDelay},
+	}
+	for _, test := range tests {
+		data, err := WalkthroughGIF(trace, test.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		animation, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(animation.Image) != test.frames {
+			t.Fatalf("%+v: expected %d frames, got %d", test.opts, test.frames, len(animation.Image))
+		}
+		if animation.Delay[0] != test.delay || animation.Delay[len(animation.Delay)-1] != 2*test.delay {
+			t.Fatalf("%+v: unexpected delays %v", test.opts, animation.Delay)
+		}
+		// 过渡帧只停留很短的时间
+		if test.frames > len(trace.Frames) && animation.Delay[1] != tweenDelay {
+			t.Fatalf("%+v: unexpected delays %v", test.opts, animation.Delay)
+		}
+		bounds := animation.Image[0].Bounds()
+		for _, frame := range animation.Image {
+			if frame.Bounds() != bounds {
+				t.Fatalf("%+v: frames have different bounds", test.opts)
+			}
+		}
+	}
+}
+
+func TestGlyphs(t *testing.T) {
+	for r, g := range glyphs {
+		for _, bits := range g {
+			if bits >= 1<<glyphWidth {
+				t.Errorf("glyph %q is wider than %d", r, glyphWidth)
+			}
+		}
+	}
+	// 字体中没有的字符绘制为方框，大写字母按小写字母绘制
+	if glyph('中') != unknownGlyph || glyph('M') != glyphs['m'] {
+		t.Fatal("unexpected glyph fallback")
+	}
+}
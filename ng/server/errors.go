@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/AyakuraYuki/go-starrail-compass/ng"
+)
+
+// 错误响应中的错误码
+const (
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeInvalidRequest   = "invalid_request"
+	CodeInvalidCompass   = "invalid_compass"
+	CodeInvalidSteps     = "invalid_steps"
+	CodeUnknownSolver    = "unknown_solver"
+	CodeUnknownCost      = "unknown_cost"
+	CodeNoSolution       = "no_solution"
+	CodeUnsupported      = "unsupported_constraints"
+	CodeTooManyStates    = "too_many_states"
+	CodeTimeout          = "timeout"
+	CodeCanceled         = "canceled"
+	CodeInternal         = "internal"
+)
+
+// Error 结构化的错误响应
+type Error struct {
+	Status   int       `json:"-"`
+	Code     string    `json:"code"`
+	Message  string    `json:"message"`
+	Progress *progress `json:"progress,omitempty"` // 求解超时或被取消时的搜索进度
+}
+
+// Error 实现 error 接口
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// errorResponse 出错时的响应正文
+type errorResponse struct {
+	Error *Error `json:"error"`
+}
+
+// progress ng.SearchProgress 的 JSON 表述
+type progress struct {
+	Tried int `json:"tried"`
+	Total int `json:"total"`
+	Found int `json:"found"`
+}
+
+// invalidRequest 创建请求有误的错误
+func invalidRequest(code, format string, args ...any) *Error {
+	return &Error{Status: http.StatusBadRequest, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// solveError 将求解器返回的错误转换为结构化的错误
+func solveError(err error) *Error {
+	e := &Error{Status: http.StatusInternalServerError, Code: CodeInternal, Message: err.Error()}
+	switch {
+	case errors.Is(err, ng.ErrNoSolution):
+		e.Status, e.Code = http.StatusUnprocessableEntity, CodeNoSolution
+	case errors.Is(err, ng.ErrUnsupportedConstraints):
+		e.Status, e.Code = http.StatusUnprocessableEntity, CodeUnsupported
+	case errors.Is(err, ng.ErrTooManyStates):
+		e.Status, e.Code = http.StatusUnprocessableEntity, CodeTooManyStates
+	case errors.Is(err, context.DeadlineExceeded):
+		e.Status, e.Code = http.StatusGatewayTimeout, CodeTimeout
+	case errors.Is(err, context.Canceled):
+		e.Status, e.Code = http.StatusServiceUnavailable, CodeCanceled
+	}
+	var searchErr *ng.SearchError
+	if errors.As(err, &searchErr) {
+		e.Progress = &progress{Tried: searchErr.Progress.Tried, Total: searchErr.Progress.Total, Found: searchErr.Progress.Found}
+	}
+	return e
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/AyakuraYuki/go-starrail-compass/ng"
+)
+
+// solveResponse POST /solve 的响应正文
+type solveResponse struct {
+	Compass   string            `json:"compass"`
+	Solution  *ng.SolutionJSON  `json:"solution,omitempty"`
+	Solutions []ng.SolutionJSON `json:"solutions,omitempty"`
+}
+
+// checkResponse POST /check 的响应正文
+type checkResponse struct {
+	Compass string        `json:"compass"`
+	Steps   []ng.StepJSON `json:"steps"`
+	Solved  bool          `json:"solved"`
+	// 解谜步骤违反 Compass.Constraints 时的原因
+	Violation string `json:"violation,omitempty"`
+}
+
+// handleSolve 求解罗盘
+func (s *Server) handleSolve(r *http.Request, req *request) (any, error) {
+	if req.All {
+		solutions, err := s.allSolutions(r, req)
+		if err != nil {
+			return nil, err
+		}
+		result := solveResponse{Compass: req.Compass.String(), Solutions: make([]ng.SolutionJSON, len(solutions))}
+		for i, solution := range solutions {
+			result.Solutions[i] = ng.NewSolutionJSON(req.Compass, solution)
+		}
+		return result, nil
+	}
+
+	solution, err := s.solve(r, req)
+	if err != nil {
+		return nil, err
+	}
+	result := ng.NewSolutionJSON(req.Compass, solution)
+	return solveResponse{Compass: req.Compass.String(), Solution: &result}, nil
+}
+
+// handleCheck 检查解谜步骤能否完成罗盘
+func (s *Server) handleCheck(_ *http.Request, req *request) (any, error) {
+	steps, ok, err := req.steps()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, invalidRequest(CodeInvalidSteps, "missing steps")
+	}
+	result := checkResponse{Compass: req.Compass.String(), Steps: ng.NewStepsJSON(req.Compass, steps)}
+	solved, err := ng.CheckSolution(req.Compass, steps)
+	var violation *ng.ConstraintViolation
+	switch {
+	case errors.As(err, &violation):
+		result.Violation = violation.Error()
+	case err != nil:
+		return nil, invalidRequest(CodeInvalidSteps, "%v", err)
+	}
+	result.Solved = solved
+	return result, nil
+}
+
+// handleSimulate 逐次转动罗盘，没有给出解谜步骤时使用最优解法
+func (s *Server) handleSimulate(r *http.Request, req *request) (any, error) {
+	steps, ok, err := req.steps()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if steps, err = s.solve(r, req); err != nil {
+			return nil, err
+		}
+	}
+	trace, err := ng.Simulate(req.Compass, steps)
+	if err != nil {
+		return nil, invalidRequest(CodeInvalidSteps, "%v", err)
+	}
+	return trace, nil
+}
+
+// solversResponse GET /solvers 的响应正文
+type solversResponse struct {
+	Solvers []ng.SolverInfo `json:"solvers"`
+}
+
+// handleSolvers 列出已注册的求解器
+func (s *Server) handleSolvers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", http.MethodGet)
+		s.writeError(w, r, &Error{Status: http.StatusMethodNotAllowed, Code: CodeMethodNotAllowed, Message: fmt.Sprintf("method %s is not allowed", r.Method)})
+		return
+	}
+	s.writeJSON(w, http.StatusOK, solversResponse{Solvers: ng.Solvers()})
+}
+
+// solve 在请求的超时时间内求出最优解法
+func (s *Server) solve(r *http.Request, req *request) (ng.Steps, error) {
+	solver, err := s.newSolver(req)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel, err := s.withTimeout(r.Context(), req)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	solution, err := solver.Solve(ctx, req.Compass)
+	if err != nil {
+		return nil, solveError(err)
+	}
+	return solution, nil
+}
+
+// allSolutions 在请求的超时时间内求出全部解法，罗盘无解时返回错误
+func (s *Server) allSolutions(r *http.Request, req *request) ([]ng.Steps, error) {
+	solver, err := s.newSolver(req)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel, err := s.withTimeout(r.Context(), req)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	solutions, err := solver.AllSolutions(ctx, req.Compass)
+	if err != nil {
+		return nil, solveError(err)
+	}
+	if len(solutions) == 0 {
+		return nil, solveError(ng.ErrNoSolution)
+	}
+	return solutions, nil
+}
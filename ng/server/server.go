@@ -0,0 +1,241 @@
+// Package server 通过 HTTP JSON API 提供引航罗盘求解器
+//
+// 全部接口都使用 POST 方法，请求与响应的正文都是 JSON：
+//
+//	POST /solve     求出最优解法，"all": true 时求出一个周期内的全部解法
+//	POST /check     检查 "steps" 给出的解谜步骤能否完成罗盘
+//	POST /simulate  逐次转动罗盘，"steps" 为空时使用最优解法
+//	GET  /solvers   列出可以通过 "solver" 选择的求解器，参见 ng.Solvers
+//
+// 请求中的 "compass" 可以是 ng.ParseCompass 支持的罗盘信息表达式字符串，也可以是 ng.Compass 的 JSON 对象；
+// "steps" 可以是 om1,mi3 这样的字符串，也可以是 [{"ringGroup":"om","count":1}] 这样的数组。
+// 出错时返回对应的 HTTP 状态码以及 {"error": {"code": "...", "message": "..."}}
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/AyakuraYuki/go-starrail-compass/ng"
+)
+
+// 默认选项
+const (
+	DefaultTimeout    = 10 * time.Second
+	DefaultMaxTimeout = 30 * time.Second
+	DefaultSolver     = ng.DefaultSolverName // 罗盘带有限制时使用 ng.ConstrainedSolverName
+	DefaultCost       = ng.DefaultCostName
+
+	maxBodyBytes = 1 << 20
+)
+
+// Options 服务的选项
+type Options struct {
+	Logger logr.Logger
+	// 请求没有指定 "timeout" 时求解的超时时间，默认为 DefaultTimeout
+	Timeout time.Duration
+	// 请求可以指定的最长超时时间，默认为 DefaultMaxTimeout
+	MaxTimeout time.Duration
+}
+
+// Server 引航罗盘求解服务，实现 http.Handler 接口
+type Server struct {
+	logger     logr.Logger
+	timeout    time.Duration
+	maxTimeout time.Duration
+	mux        *http.ServeMux
+}
+
+// New 创建引航罗盘求解服务
+func New(opts Options) *Server {
+	s := &Server{
+		logger:     opts.Logger,
+		timeout:    opts.Timeout,
+		maxTimeout: opts.MaxTimeout,
+		mux:        http.NewServeMux(),
+	}
+	if s.timeout <= 0 {
+		s.timeout = DefaultTimeout
+	}
+	if s.maxTimeout <= 0 {
+		s.maxTimeout = DefaultMaxTimeout
+	}
+	if s.timeout > s.maxTimeout {
+		s.timeout = s.maxTimeout
+	}
+	s.handle("/solve", s.handleSolve)
+	s.handle("/check", s.handleCheck)
+	s.handle("/simulate", s.handleSimulate)
+	s.mux.HandleFunc("/solvers", s.handleSolvers)
+	return s
+}
+
+// ServeHTTP 实现 http.Handler 接口
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// handle 注册只接受 POST 方法的接口，handler 返回的错误会被转换为结构化的错误响应
+func (s *Server) handle(pattern string, handler func(r *http.Request, req *request) (any, error)) {
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			s.writeError(w, r, &Error{Status: http.StatusMethodNotAllowed, Code: CodeMethodNotAllowed, Message: fmt.Sprintf("method %s is not allowed", r.Method)})
+			return
+		}
+
+		req, err := decodeRequest(w, r)
+		if err != nil {
+			s.writeError(w, r, err)
+			return
+		}
+		result, err := handler(r, req)
+		if err != nil {
+			s.writeError(w, r, err)
+			return
+		}
+		s.writeJSON(w, http.StatusOK, result)
+	})
+}
+
+// request 各接口共用的请求正文
+type request struct {
+	RawCompass json.RawMessage `json:"compass"`
+	Steps      json.RawMessage `json:"steps"`
+	Solver     string          `json:"solver"` // 为空时使用 ng.DefaultSolverFor 选择的求解器
+	Cost       string          `json:"cost"`
+	Timeout    string          `json:"timeout"` // 例如 500ms 或 2s
+	All        bool            `json:"all"`
+
+	Compass ng.Compass `json:"-"` // 由 RawCompass 解析得到的罗盘
+}
+
+// decodeRequest 解析请求正文
+func decodeRequest(w http.ResponseWriter, r *http.Request) (*request, error) {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	decoder.DisallowUnknownFields()
+	req := &request{}
+	if err := decoder.Decode(req); err != nil {
+		return nil, invalidRequest(CodeInvalidRequest, "decode request error: %v", err)
+	}
+	if decoder.More() {
+		return nil, invalidRequest(CodeInvalidRequest, "unexpected data after the request object")
+	}
+	if raw := strings.TrimSpace(string(req.RawCompass)); raw == "" || raw == "null" {
+		return nil, invalidRequest(CodeInvalidCompass, "missing compass")
+	}
+	if err := json.Unmarshal(req.RawCompass, &req.Compass); err != nil {
+		return nil, invalidRequest(CodeInvalidCompass, "%v", err)
+	}
+	if err := req.Compass.Validate(); err != nil {
+		return nil, invalidRequest(CodeInvalidCompass, "invalid compass: %v", err)
+	}
+	return req, nil
+}
+
+// steps 解析请求中的解谜步骤，没有给出时 ok 为 false
+func (req *request) steps() (steps ng.Steps, ok bool, err error) {
+	raw := strings.TrimSpace(string(req.Steps))
+	if raw == "" || raw == "null" {
+		return nil, false, nil
+	}
+
+	rings := len(req.Compass.Rings)
+	var expression string
+	if strings.HasPrefix(raw, `"`) {
+		if err := json.Unmarshal(req.Steps, &expression); err != nil {
+			return nil, true, invalidRequest(CodeInvalidSteps, "decode steps error: %v", err)
+		}
+	} else {
+		// 方案的缩写与罗盘的圈数有关，因此不能直接解析为 ng.Steps
+		var content []struct {
+			RingGroup string `json:"ringGroup"`
+			Count     int    `json:"count"`
+		}
+		if err := json.Unmarshal(req.Steps, &content); err != nil {
+			return nil, true, invalidRequest(CodeInvalidSteps, "decode steps error: %v", err)
+		}
+		parts := make([]string, len(content))
+		for i, step := range content {
+			if step.Count < 0 {
+				return nil, true, invalidRequest(CodeInvalidSteps, "step %d has a negative count: %d", i, step.Count)
+			}
+			parts[i] = fmt.Sprintf("%s%d", step.RingGroup, step.Count)
+		}
+		expression = strings.Join(parts, ",")
+	}
+
+	steps, err = ng.ParseSteps(expression, rings)
+	if err != nil {
+		return nil, true, invalidRequest(CodeInvalidSteps, "%v", err)
+	}
+	for _, step := range steps {
+		if !req.Compass.IsRingGroupSupported(step.RingGroup) {
+			return nil, true, invalidRequest(CodeInvalidSteps, "ring group %s is not supported by the compass", step.RingGroup.Format(rings))
+		}
+	}
+	return steps, true, nil
+}
+
+// withTimeout 按请求中的 "timeout" 创建求解使用的 context
+func (s *Server) withTimeout(ctx context.Context, req *request) (context.Context, context.CancelFunc, error) {
+	timeout := s.timeout
+	if req.Timeout != "" {
+		d, err := time.ParseDuration(req.Timeout)
+		if err != nil || d <= 0 {
+			return nil, nil, invalidRequest(CodeInvalidRequest, "invalid timeout %q", req.Timeout)
+		}
+		timeout = min(d, s.maxTimeout)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, cancel, nil
+}
+
+// newSolver 按请求中的 "solver" 与 "cost" 创建求解器
+func (s *Server) newSolver(req *request) (ng.Solver, error) {
+	name := req.Solver
+	if name == "" {
+		name = ng.DefaultSolverFor(req.Compass)
+	}
+	costName := req.Cost
+	if costName == "" {
+		costName = DefaultCost
+	}
+	cost, err := ng.CostFuncByName(costName)
+	if err != nil {
+		return nil, invalidRequest(CodeUnknownCost, "%v", err)
+	}
+	solver, err := ng.NewSolver(name, ng.SolverOptions{Logger: s.logger, Cost: cost})
+	if errors.Is(err, ng.ErrUnknownSolver) {
+		return nil, invalidRequest(CodeUnknownSolver, "%v", err)
+	}
+	return solver, err
+}
+
+// writeJSON 输出 JSON 响应
+func (s *Server) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		s.logger.Error(err, "encode response error")
+	}
+}
+
+// writeError 输出结构化的错误响应
+func (s *Server) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var e *Error
+	if !errors.As(err, &e) {
+		e = &Error{Status: http.StatusInternalServerError, Code: CodeInternal, Message: err.Error()}
+	}
+	if e.Status >= http.StatusInternalServerError {
+		s.logger.Error(err, "request failed", "path", r.URL.Path)
+	}
+	s.writeJSON(w, e.Status, errorResponse{Error: e})
+}
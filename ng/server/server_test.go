@@ -0,0 +1,97 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServer(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		status   int
+		contains string
+	}{
+		{"solve", "POST", "/solve", `{"compass":"0+3,3-3,0+2/mi,om,oi"}`, http.StatusOK, `"expression":"mi3"`},
+		{"solve object", "POST", "/solve", `{"compass":{"rings":[{"location":0,"speed":2},{"location":3,"speed":-3},{"location":0,"speed":3}],"ringGroups":["mi","om","oi"]},"solver":"hunger"}`, http.StatusOK, `"expression":"mi3"`},
+		{"solve all", "POST", "/solve", `{"compass":"0+2,4-4,0+1/mi,oi,om","all":true}`, http.StatusOK, `"solutions":[`},
+		{"solve cost", "POST", "/solve", `{"compass":"3+3,0+1,3+1/o,i,oi","cost":"animation"}`, http.StatusOK, `"expression":"i2,oi1"`},
+		{"solve switches cost", "POST", "/solve", `{"compass":"3+3,0+1,3+1/o,i,oi","cost":"switches"}`, http.StatusOK, `"expression":"oi3"`},
+		{"solve generalized", "POST", "/solve", `{"compass":"5+1>2,6-2,7+3>7,4+5/ab,dc,ad,b@8"}`, http.StatusOK, `"ringGroup":"ab"`},
+		{"solve no solution", "POST", "/solve", `{"compass":"1+2,0+2,0+2/om,oi,mi"}`, http.StatusUnprocessableEntity, `"code":"no_solution"`},
+		{"solve constraints", "POST", "/solve", `{"compass":{"rings":[{"location":0,"speed":2},{"location":3,"speed":-3},{"location":0,"speed":3}],"ringGroups":["mi","om","oi"],"constraints":{"maxGroupPresses":{"mi":1}}},"solver":"bfs"}`, http.StatusOK, `"expression":"mi1,oi2"`},
+		{"solve too many states", "POST", "/solve", `{"compass":"0+1,0+1,0+1,0+1,0+1,0+1,0+1,1+1/a,b,c,d,e,f,g,h@12","solver":"bfs"}`, http.StatusUnprocessableEntity, `"code":"too_many_states"`},
+		{"solve constraints default solver", "POST", "/solve", `{"compass":{"rings":[{"location":0,"speed":2},{"location":3,"speed":-3},{"location":0,"speed":3}],"ringGroups":["mi","om","oi"],"constraints":{"maxGroupPresses":{"mi":1}}}}`, http.StatusOK, `"expression":"mi1,oi2"`},
+		{"solve unsupported constraints", "POST", "/solve", `{"compass":{"rings":[{"location":0,"speed":2},{"location":3,"speed":-3},{"location":0,"speed":3}],"ringGroups":["mi","om","oi"],"constraints":{"maxGroupPresses":{"mi":1}}},"solver":"linear"}`, http.StatusUnprocessableEntity, `"code":"unsupported_constraints"`},
+		{"solve unknown solver", "POST", "/solve", `{"compass":"0+3,3-3,0+2/mi,om,oi","solver":"magic"}`, http.StatusBadRequest, `"code":"unknown_solver"`},
+		{"solve unknown cost", "POST", "/solve", `{"compass":"0+3,3-3,0+2/mi,om,oi","cost":"magic"}`, http.StatusBadRequest, `"code":"unknown_cost"`},
+		{"solve invalid timeout", "POST", "/solve", `{"compass":"0+3,3-3,0+2/mi,om,oi","timeout":"soon"}`, http.StatusBadRequest, `"code":"invalid_request"`},
+		{"solve timeout", "POST", "/solve", `{"compass":"0+3,3-3,0+2/mi,om,oi","solver":"hunger","timeout":"1ns"}`, http.StatusGatewayTimeout, `"code":"timeout"`},
+		{"solve timeout linear", "POST", "/solve", `{"compass":"0+1,0+1,0+1,1+1/a,b,c,d,ab,ac,ad,bc,bd,cd,abc,abd,acd,bcd,abcd@12","timeout":"50ms"}`, http.StatusGatewayTimeout, `"code":"timeout"`},
+		{"invalid compass", "POST", "/solve", `{"compass":"hello"}`, http.StatusBadRequest, `"code":"invalid_compass"`},
+		{"missing compass", "POST", "/solve", `{}`, http.StatusBadRequest, `"code":"invalid_compass"`},
+		{"unknown field", "POST", "/solve", `{"compass":"0+3,3-3,0+2/mi,om,oi","magic":1}`, http.StatusBadRequest, `"code":"invalid_request"`},
+		{"invalid json", "POST", "/solve", `{`, http.StatusBadRequest, `"code":"invalid_request"`},
+		{"method not allowed", "GET", "/solve", ``, http.StatusMethodNotAllowed, `"code":"method_not_allowed"`},
+		{"check", "POST", "/check", `{"compass":"0+3,3-3,0+2/mi,om,oi","steps":"mi3"}`, http.StatusOK, `"solved":true`},
+		{"check array", "POST", "/check", `{"compass":"0+3,3-3,0+2/mi,om,oi","steps":[{"ringGroup":"om","count":1}]}`, http.StatusOK, `"solved":false`},
+		{"check violation", "POST", "/check", `{"compass":{"rings":[{"location":0,"speed":2},{"location":3,"speed":-3},{"location":0,"speed":3}],"ringGroups":["mi","om","oi"],"constraints":{"maxGroupPresses":{"mi":1}}},"steps":"mi3"}`, http.StatusOK, `"violation":"press 2 exceeds the limit of 1 presses for ring group mi"`},
+		{"check missing steps", "POST", "/check", `{"compass":"0+3,3-3,0+2/mi,om,oi"}`, http.StatusBadRequest, `"code":"invalid_steps"`},
+		{"check unsupported group", "POST", "/check", `{"compass":"0+3,3-3,0+2/mi,om,oi","steps":"o1"}`, http.StatusBadRequest, `"code":"invalid_steps"`},
+		{"check negative count", "POST", "/check", `{"compass":"0+3,3-3,0+2/mi,om,oi","steps":[{"ringGroup":"mi","count":-1}]}`, http.StatusBadRequest, `"code":"invalid_steps"`},
+		{"simulate", "POST", "/simulate", `{"compass":"0+3,3-3,0+2/mi,om,oi"}`, http.StatusOK, `"solved":true`},
+		{"simulate violation", "POST", "/simulate", `{"compass":{"rings":[{"location":0,"speed":2},{"location":3,"speed":-3},{"location":0,"speed":3}],"ringGroups":["mi","om","oi"],"constraints":{"maxGroupPresses":{"mi":1}}},"steps":"mi3"}`, http.StatusOK, `"violation":"press 2 exceeds the limit of 1 presses for ring group mi"`},
+		{"simulate steps", "POST", "/simulate", `{"compass":"0+3,3-3,0+2/mi,om,oi","steps":"om1"}`, http.StatusOK, `"solved":false`},
+		{"solvers", "GET", "/solvers", ``, http.StatusOK, `"name":"linear"`},
+		{"solvers method not allowed", "POST", "/solvers", `{}`, http.StatusMethodNotAllowed, `"code":"method_not_allowed"`},
+		{"not found", "POST", "/magic", `{}`, http.StatusNotFound, ``},
+	}
+
+	handler := New(Options{})
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(test.method, test.path, strings.NewReader(test.body)))
+			if recorder.Code != test.status {
+				t.Fatalf("unexpected status %d (expected: %d): %s", recorder.Code, test.status, recorder.Body)
+			}
+			if !strings.Contains(recorder.Body.String(), test.contains) {
+				t.Fatalf("response does not contain %s: %s", test.contains, recorder.Body)
+			}
+		})
+	}
+}
+
+func TestServer_ErrorResponse(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	body := `{"compass":"0+3,3-3,0+2/mi,om,oi","solver":"hunger","timeout":"1ns"}`
+	New(Options{}).ServeHTTP(recorder, httptest.NewRequest("POST", "/solve", strings.NewReader(body)))
+
+	if contentType := recorder.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "application/json") {
+		t.Fatalf("unexpected content type: %s", contentType)
+	}
+	var response struct {
+		Error struct {
+			Code     string `json:"code"`
+			Message  string `json:"message"`
+			Progress *struct {
+				Tried int `json:"tried"`
+				Total int `json:"total"`
+			} `json:"progress"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Error.Code != CodeTimeout || response.Error.Message == "" {
+		t.Fatalf("unexpected error: %+v", response.Error)
+	}
+	if response.Error.Progress == nil || response.Error.Progress.Total != 216 {
+		t.Fatalf("unexpected progress: %s", recorder.Body)
+	}
+}
@@ -0,0 +1,191 @@
+package ng
+
+// smithForm 整数矩阵的史密斯标准型分解
+// 满足 U · A · V = D，其中 U、V 是整数上的幺模矩阵（行列式为 ±1），
+// D 是对角矩阵，并且对角线上的元素依次整除（d0 | d1 | d2 ...）
+type smithForm struct {
+	U [][]int // 行变换矩阵，rows x rows
+	D [][]int // 对角矩阵，rows x cols
+	V [][]int // 列变换矩阵，cols x cols
+}
+
+// Diagonal 返回对角线上的元素
+func (f *smithForm) Diagonal() []int {
+	n := len(f.D)
+	if n > 0 && len(f.D[0]) < n {
+		n = len(f.D[0])
+	}
+	diagonal := make([]int, n)
+	for i := range diagonal {
+		diagonal[i] = f.D[i][i]
+	}
+	return diagonal
+}
+
+// newSmithForm 计算整数矩阵 a 的史密斯标准型
+func newSmithForm(a [][]int) *smithForm {
+	rows := len(a)
+	cols := 0
+	if rows > 0 {
+		cols = len(a[0])
+	}
+
+	f := &smithForm{
+		U: identityMatrix(rows),
+		D: copyMatrix(a),
+		V: identityMatrix(cols),
+	}
+
+	for t := 0; t < rows && t < cols; t++ {
+		// 选取剩余子矩阵中绝对值最小的非零元素作为主元
+		pr, pc, ok := f.findPivot(t)
+		if !ok {
+			break
+		}
+		f.swapRows(t, pr)
+		f.swapCols(t, pc)
+
+		for {
+			// 用主元消去同一列、同一行的其他元素，余数不为零时更换主元继续消去
+			if f.reduceColumn(t) || f.reduceRow(t) {
+				continue
+			}
+			// 保证主元整除剩余子矩阵的所有元素
+			if i, ok := f.findIndivisibleRow(t); ok {
+				f.addRow(t, i, 1)
+				continue
+			}
+			break
+		}
+
+		if f.D[t][t] < 0 {
+			f.negateRow(t)
+		}
+	}
+
+	return f
+}
+
+// findPivot 在 [t:, t:] 子矩阵中寻找绝对值最小的非零元素
+func (f *smithForm) findPivot(t int) (int, int, bool) {
+	pr, pc, found := 0, 0, false
+	for i := t; i < len(f.D); i++ {
+		for j := t; j < len(f.D[i]); j++ {
+			if f.D[i][j] == 0 {
+				continue
+			}
+			if !found || Abs(f.D[i][j]) < Abs(f.D[pr][pc]) {
+				pr, pc, found = i, j, true
+			}
+		}
+	}
+	return pr, pc, found
+}
+
+// reduceColumn 消去第 t 列主元以下的元素，若出现更小的余数则换为新的主元并返回 true
+func (f *smithForm) reduceColumn(t int) bool {
+	for i := t + 1; i < len(f.D); i++ {
+		if f.D[i][t] == 0 {
+			continue
+		}
+		f.addRow(i, t, -(f.D[i][t] / f.D[t][t]))
+		if f.D[i][t] != 0 {
+			f.swapRows(t, i)
+			return true
+		}
+	}
+	return false
+}
+
+// reduceRow 消去第 t 行主元右侧的元素，若出现更小的余数则换为新的主元并返回 true
+func (f *smithForm) reduceRow(t int) bool {
+	for j := t + 1; j < len(f.D[t]); j++ {
+		if f.D[t][j] == 0 {
+			continue
+		}
+		f.addCol(j, t, -(f.D[t][j] / f.D[t][t]))
+		if f.D[t][j] != 0 {
+			f.swapCols(t, j)
+			return true
+		}
+	}
+	return false
+}
+
+// findIndivisibleRow 寻找剩余子矩阵中包含不能被主元整除的元素的行
+func (f *smithForm) findIndivisibleRow(t int) (int, bool) {
+	for i := t + 1; i < len(f.D); i++ {
+		for j := t + 1; j < len(f.D[i]); j++ {
+			if f.D[i][j]%f.D[t][t] != 0 {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// swapRows 交换 D 的两行，并同步到 U
+func (f *smithForm) swapRows(a, b int) {
+	f.D[a], f.D[b] = f.D[b], f.D[a]
+	f.U[a], f.U[b] = f.U[b], f.U[a]
+}
+
+// swapCols 交换 D 的两列，并同步到 V
+func (f *smithForm) swapCols(a, b int) {
+	for i := range f.D {
+		f.D[i][a], f.D[i][b] = f.D[i][b], f.D[i][a]
+	}
+	for i := range f.V {
+		f.V[i][a], f.V[i][b] = f.V[i][b], f.V[i][a]
+	}
+}
+
+// addRow 将第 src 行的 k 倍加到第 dst 行
+func (f *smithForm) addRow(dst, src, k int) {
+	for j := range f.D[dst] {
+		f.D[dst][j] += k * f.D[src][j]
+	}
+	for j := range f.U[dst] {
+		f.U[dst][j] += k * f.U[src][j]
+	}
+}
+
+// addCol 将第 src 列的 k 倍加到第 dst 列
+func (f *smithForm) addCol(dst, src, k int) {
+	for i := range f.D {
+		f.D[i][dst] += k * f.D[i][src]
+	}
+	for i := range f.V {
+		f.V[i][dst] += k * f.V[i][src]
+	}
+}
+
+// negateRow 将第 t 行取反
+func (f *smithForm) negateRow(t int) {
+	for j := range f.D[t] {
+		f.D[t][j] = -f.D[t][j]
+	}
+	for j := range f.U[t] {
+		f.U[t][j] = -f.U[t][j]
+	}
+}
+
+// identityMatrix 创建 n 阶单位矩阵
+func identityMatrix(n int) [][]int {
+	m := make([][]int, n)
+	for i := range m {
+		m[i] = make([]int, n)
+		m[i][i] = 1
+	}
+	return m
+}
+
+// copyMatrix 深拷贝矩阵
+func copyMatrix(src [][]int) [][]int {
+	dst := make([][]int, len(src))
+	for i := range src {
+		dst[i] = make([]int, len(src[i]))
+		copy(dst[i], src[i])
+	}
+	return dst
+}
@@ -2,17 +2,104 @@ package ng
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
 
 	"github.com/go-logr/logr"
 )
 
+// ErrNoSolution 罗盘无解
+var ErrNoSolution = errors.New(`the compass has no solution`)
+
 // Solver 引航罗盘求解器
 type Solver interface {
 	// Solve 求解引航罗盘
 	Solve(ctx context.Context, compass Compass) (Steps, error)
+	// AllSolutions 求出一个周期内（每个方案转动 0 到总刻度值减 1 次）全部不同的标准化解法
+	// 解法按代价从低到高排列，罗盘无解时返回空列表
+	AllSolutions(ctx context.Context, compass Compass) ([]Steps, error)
 }
 
 // SolverOptions 求解器的选项
 type SolverOptions struct {
 	Logger logr.Logger
+	// 代价函数，求解器返回代价最小的解法，默认为转动总次数 TotalPresses
+	Cost CostFunc
+}
+
+// SearchProgress 求解器的搜索进度
+type SearchProgress struct {
+	Tried int   // 已经尝试的候选解法数量
+	Total int   // 候选解法的总数
+	Found int   // 已经找到的可行解法数量
+	Best  Steps // 目前为止代价最小的解法，Found 为 0 时无意义
+}
+
+// SearchError 搜索被取消或超时时返回的错误，携带中断时的搜索进度
+// 可以通过 errors.Is 判断是否为 context.Canceled 或 context.DeadlineExceeded
+type SearchError struct {
+	Progress SearchProgress
+	Err      error
+}
+
+// Error 实现 error 接口
+func (e *SearchError) Error() string {
+	best := "none"
+	if e.Progress.Found > 0 {
+		best = fmt.Sprintf(`"%s"`, e.Progress.Best.String())
+	}
+	return fmt.Sprintf(`search interrupted after %d of %d candidates (found %d, best %s): %v`,
+		e.Progress.Tried, e.Progress.Total, e.Progress.Found, best, e.Err)
+}
+
+// Unwrap 返回导致搜索中断的原始错误
+func (e *SearchError) Unwrap() error {
+	return e.Err
+}
+
+// newSteps 按方案顺序将每个方案的转动次数组合为标准化的解法
+func newSteps(ringGroups []RingGroup, counts []int) Steps {
+	steps := make(Steps, len(ringGroups))
+	for i, rg := range ringGroups {
+		steps[i] = Step{RingGroup: rg, Count: counts[i]}
+	}
+	return steps.Standardize()
+}
+
+// sortSolutions 对解法去重，并按代价从低到高排序
+func sortSolutions(compass Compass, cost CostFunc, solutions []Steps) []Steps {
+	seen := make(map[string]bool, len(solutions))
+	distinct := make([]Steps, 0, len(solutions))
+	for _, solution := range solutions {
+		key := solution.Format(len(compass.Rings))
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		distinct = append(distinct, solution)
+	}
+
+	sort.SliceStable(distinct, func(i, j int) bool {
+		return lessSolution(compass, cost, distinct[i], distinct[j])
+	})
+	return distinct
+}
+
+// uniqueRingGroups 按原有顺序对方案去重
+func uniqueRingGroups(ringGroups []RingGroup) []RingGroup {
+	unique := make([]RingGroup, 0, len(ringGroups))
+	for _, rg := range ringGroups {
+		duplicated := false
+		for _, v := range unique {
+			if v == rg {
+				duplicated = true
+				break
+			}
+		}
+		if !duplicated {
+			unique = append(unique, rg)
+		}
+	}
+	return unique
 }
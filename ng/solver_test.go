@@ -2,9 +2,12 @@ package ng
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/bombsimon/logrusr/v4"
 	"github.com/sirupsen/logrus"
 	"testing"
+	"time"
 )
 
 func TestSolver_Solve(t *testing.T) {
@@ -29,3 +32,290 @@ func TestSolver_Solve(t *testing.T) {
 	t.Log(compass.String())
 	t.Log(solution.String())
 }
+
+func TestSolver_AllSolutions(t *testing.T) {
+	hunger, err := NewHungerSolver(SolverOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	linear, err := NewLinearSolver(SolverOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expressions := []string{
+		"0+3,3-3,0+2/mi,mo,io",
+		"0+2,3-3,0+3/mi,om,oi",
+		"0+2,4-4,0+1/mi,oi,om",
+		"1+2,0+2,0+2/om,oi,mi",
+		"2+1,3-2,1+3/o,mi",
+	}
+	for _, expression := range expressions {
+		compass, err := ParseCompass(expression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(compass.RingGroups) == 2 {
+			// 表达式不允许重复的方案，这里直接在罗盘中追加，检查求解器能够处理重复的方案
+			compass.RingGroups = append(compass.RingGroups, MiddleInner)
+		}
+
+		expected, err := hunger.AllSolutions(context.Background(), compass)
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual, err := linear.AllSolutions(context.Background(), compass)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(expected) != len(actual) {
+			t.Fatalf("%s: solvers disagree on solution count: hunger=%v, linear=%v", expression, expected, actual)
+		}
+		for i := range expected {
+			if expected[i].String() != actual[i].String() {
+				t.Fatalf("%s: solvers disagree at %d: hunger=%s, linear=%s", expression, i, expected[i], actual[i])
+			}
+			if ok, err := CheckSolution(compass, actual[i]); err != nil || !ok {
+				t.Fatalf("%s: solution %s does not pass check: %v", expression, actual[i], err)
+			}
+		}
+		t.Logf("%s: %d solutions %v", expression, len(actual), actual)
+	}
+}
+
+// countdownContext 在 Err 被调用指定次数之后变为已取消的 context，用于在搜索中途取消
+type countdownContext struct {
+	context.Context
+	remaining int
+}
+
+func (c *countdownContext) Err() error {
+	if c.remaining <= 0 {
+		return context.Canceled
+	}
+	c.remaining--
+	return nil
+}
+
+func TestHungerSolver_Cancel(t *testing.T) {
+	solver, err := NewHungerSolver(SolverOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	compass, err := ParseCompass("0+3,3-3,0+2/mi,mo,io")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 第一个可行解法 mi3 在第 4 个候选解法处出现，之后取消
+	ctx := &countdownContext{Context: context.Background(), remaining: 100}
+	_, err = solver.Solve(ctx, compass)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("unexpected error: %v (expected: %v)", err, context.Canceled)
+	}
+	var searchErr *SearchError
+	if !errors.As(err, &searchErr) {
+		t.Fatalf("unexpected error type: %T", err)
+	}
+	progress := searchErr.Progress
+	if progress.Tried != 100 || progress.Total != SCALES*SCALES*SCALES {
+		t.Errorf("unexpected progress: %d/%d", progress.Tried, progress.Total)
+	}
+	if progress.Found == 0 {
+		t.Fatal("expected at least one solution before cancellation")
+	}
+	if ok, err := CheckSolution(compass, progress.Best); err != nil || !ok {
+		t.Errorf("best solution so far %s does not pass check: %v", progress.Best, err)
+	}
+	t.Log(err)
+
+	// AllSolutions 同样可以被取消
+	ctx = &countdownContext{Context: context.Background(), remaining: 10}
+	if _, err := solver.AllSolutions(ctx, compass); !errors.As(err, &searchErr) || searchErr.Progress.Tried != 10 {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestHungerSolver_Deadline(t *testing.T) {
+	solver, err := NewHungerSolver(SolverOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	compass, err := ParseCompass("0+3,3-3,0+2/mi,mo,io")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+	_, err = solver.Solve(ctx, compass)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("unexpected error: %v (expected: %v)", err, context.DeadlineExceeded)
+	}
+	var searchErr *SearchError
+	if !errors.As(err, &searchErr) || searchErr.Progress.Tried != 0 || searchErr.Progress.Found != 0 {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSolver_Generalized(t *testing.T) {
+	hunger, err := NewHungerSolver(SolverOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	linear, err := NewLinearSolver(SolverOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expressions := []string{
+		"5+1,6-2,7+3,4+5/ab,dc,ad,b@8",
+		"5+3,6-2,0+4,6+2/abc,bd,cd@8",
+		"5+5,1-1,7+7,10+2,6-11/abcde,e,bd,ac@12",
+		"0+1,3-2,1+3,7+5/ab,dc,ad,b@8",
+		"1+1,4-1/a,b@5",
+	}
+	for _, expression := range expressions {
+		compass, err := ParseCompass(expression)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected, err := hunger.AllSolutions(context.Background(), compass)
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual, err := linear.AllSolutions(context.Background(), compass)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(expected) != len(actual) {
+			t.Fatalf("%s: solvers disagree on solution count: hunger=%d, linear=%d", expression, len(expected), len(actual))
+		}
+		for i := range actual {
+			if expected[i].String() != actual[i].String() {
+				t.Fatalf("%s: solvers disagree at %d: hunger=%s, linear=%s", expression, i, expected[i], actual[i])
+			}
+			if ok, err := CheckSolution(compass, actual[i]); err != nil || !ok {
+				t.Fatalf("%s: solution %s does not pass check: %v", expression, actual[i], err)
+			}
+		}
+		if len(actual) > 0 {
+			t.Logf("%s: %d solutions, best %s", expression, len(actual), actual[0].Format(len(compass.Rings)))
+		} else {
+			t.Logf("%s: no solution", expression)
+		}
+	}
+}
+
+func TestSolver_Target(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   string
+	}{
+		// 目标位置都为 0 时与不声明目标位置相同
+		{"0+3>0,3-3>0,0+2>0/mi,mo,io", "mi3"},
+		// 中圈停在 ∠180° 位置即可，不需要转动
+		{"0+3,3-3>3,0+2/mi,mo,io", ""},
+		// 外圈需要停在刻度 3，内圈需要停在刻度 2
+		{"0+3>3,3-3,0+2>2/mi,mo,io", "mi1,oi3"},
+		{"5+1>2,6-2,7+3>7,4+5/ab,dc,ad,b@8", "b2,ad4,ab1"},
+	}
+	for _, info := range Solvers() {
+		if !info.Generalized || !info.Optimal {
+			continue
+		}
+		solver, err := NewSolver(info.Name, SolverOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, test := range tests {
+			compass, err := ParseCompass(test.expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			solution, err := solver.Solve(context.Background(), compass)
+			if err != nil {
+				t.Fatalf("%s: %v", test.expression, err)
+			}
+			if actual := solution.Format(len(compass.Rings)); actual != test.expected {
+				t.Errorf("%s: unexpected solution %s (expected: %s)", test.expression, actual, test.expected)
+			}
+			if ok, err := CheckSolution(compass, solution); err != nil || !ok {
+				t.Errorf("%s: solution %s does not pass check: %v", test.expression, solution, err)
+			}
+		}
+	}
+}
+
+func TestSolver_GroupSpeeds(t *testing.T) {
+	expressions := []string{
+		"0+2,3-3,0+3/m+2i-1,om,oi",
+		"4+2,0-3,4+3/m+2i-1,m-1,oi",
+		"5+1,6-2,7+3,4+5/a+2b,dc-3,ad,b+3@8",
+	}
+	for _, expression := range expressions {
+		compass, err := ParseCompass(expression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var expected []Steps
+		for _, info := range Solvers() {
+			if !info.Generalized || !info.Complete {
+				continue
+			}
+			solver, err := NewSolver(info.Name, SolverOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			solutions, err := solver.AllSolutions(context.Background(), compass)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(solutions) == 0 {
+				t.Fatalf("%s: expected solutions", expression)
+			}
+			for _, solution := range solutions {
+				if ok, err := CheckSolution(compass, solution); err != nil || !ok {
+					t.Fatalf("%s: solution %s does not pass check: %v", expression, solution, err)
+				}
+			}
+			if expected != nil && fmt.Sprint(expected) != fmt.Sprint(solutions) {
+				t.Fatalf("%s: solvers disagree: %v, %v", expression, expected, solutions)
+			}
+			expected = solutions
+		}
+		t.Logf("%s: %d solutions, best %s", expression, len(expected), expected[0].Format(len(compass.Rings)))
+	}
+}
+
+func TestCheckSolution_GroupSpeeds(t *testing.T) {
+	// 方案 mi 转动中圈 2 个刻度、内圈 -1 个刻度，而不是各圈的旋转速度
+	compass, err := ParseCompass("0+2,4-3,1+3/m+2i-1,om,oi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := CheckSolution(compass, Steps{{RingGroup: MiddleInner, Count: 1}}); err != nil || !ok {
+		t.Fatalf("unexpected result: %v, %v", ok, err)
+	}
+	if ok, err := CheckSolution(compass, Steps{{RingGroup: MiddleInner, Count: 2}}); err != nil || ok {
+		t.Fatalf("unexpected result: %v, %v", ok, err)
+	}
+}
+
+func TestSortSolutions(t *testing.T) {
+	compass, err := ParseCompass("5+1,6-2,7+3,4+5/ab,dc,ad,b@8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ab, dc, b := compass.RingGroups[0], compass.RingGroups[1], compass.RingGroups[3]
+	solutions := sortSolutions(compass, TotalPresses, []Steps{
+		{{RingGroup: ab, Count: 2}, {RingGroup: b, Count: 1}},
+		{{RingGroup: dc, Count: 1}},
+		// 与第一个解法只是顺序不同
+		{{RingGroup: b, Count: 1}, {RingGroup: ab, Count: 2}},
+	})
+	if len(solutions) != 2 || solutions[0].Format(4) != "cd1" || solutions[1].Format(4) != "b1,ab2" {
+		t.Fatalf("unexpected solutions %v", solutions)
+	}
+}
@@ -23,12 +23,17 @@ func (s *Step) Validate() error {
 	return nil
 }
 
-// String 转为字符串表述
+// String 转为经典三圈罗盘中的字符串表述
 func (s *Step) String() string {
+	return s.Format(3)
+}
+
+// Format 转为 rings 个圈的罗盘中的字符串表述
+func (s *Step) Format(rings int) string {
 	if s.Count <= 0 {
 		return ""
 	}
-	return fmt.Sprintf("%s%d", s.RingGroup.ShortName(), s.Count)
+	return fmt.Sprintf("%s%d", s.RingGroup.Format(rings), s.Count)
 }
 
 // Steps 引航罗盘解谜步骤组合
@@ -67,8 +72,24 @@ func (s Steps) Standardize() Steps {
 	return simplified
 }
 
-// String 转为字符串表述
+// Total 返回转动总次数
+func (s Steps) Total() int {
+	total := 0
+	for _, step := range s {
+		if step.Count > 0 {
+			total += step.Count
+		}
+	}
+	return total
+}
+
+// String 转为经典三圈罗盘中的字符串表述
 func (s Steps) String() string {
+	return s.Format(3)
+}
+
+// Format 转为 rings 个圈的罗盘中的字符串表述
+func (s Steps) Format(rings int) string {
 	// 标准化
 	std := s.Standardize()
 	if len(std) == 0 {
@@ -78,13 +99,14 @@ func (s Steps) String() string {
 	// 逐个转换成字符串
 	steps := make([]string, len(std))
 	for i := range std {
-		steps[i] = std[i].String()
+		steps[i] = std[i].Format(rings)
 	}
 	return strings.Join(steps, ",")
 }
 
 // CheckSolution 检查罗盘解谜步骤
 // 对穷举的解法试错，尝试捕获最小能够解谜的转动操作方案
+// 罗盘声明了 Constraints 时按步骤的顺序逐次转动，违反限制时返回 false 以及 *ConstraintViolation
 func CheckSolution(compass Compass, solution Steps) (bool, error) {
 	if err := compass.Validate(); err != nil {
 		return false, fmt.Errorf(`invalid compass, error: %w`, err)
@@ -93,31 +115,41 @@ func CheckSolution(compass Compass, solution Steps) (bool, error) {
 		return false, fmt.Errorf(`invalid solution, error: %w`, err)
 	}
 
+	// 按顺序逐次转动，检查是否违反限制
+	if !compass.Constraints.IsZero() {
+		if err := compass.Constraints.check(compass, solution); err != nil {
+			return false, err
+		}
+	}
+
 	// 各圈的初始位置
-	outer := compass.OuterRing.Location
-	middle := compass.MiddleRing.Location
-	inner := compass.InnerRing.Location
+	locations := make([]int, len(compass.Rings))
+	for i, ring := range compass.Rings {
+		locations[i] = ring.Location
+	}
 
 	// 转一下
 	for _, step := range solution {
 		if !compass.IsRingGroupSupported(step.RingGroup) {
-			return false, fmt.Errorf(`steps contains an unexpected ring group, which is not supported by compass: %s (must be one of %v)`, step.RingGroup.Name(), compass.RingGroups)
+			return false, fmt.Errorf(`steps contains an unexpected ring group, which is not supported by compass: %s (must be one of %v)`, step.RingGroup.Format(len(compass.Rings)), compass.RingGroups)
 		}
 
-		if step.RingGroup&Outer > 0 {
-			outer += step.Count * compass.OuterRing.Speed
-		}
-		if step.RingGroup&Middle > 0 {
-			middle += step.Count * compass.MiddleRing.Speed
-		}
-		if step.RingGroup&Inner > 0 {
-			inner += step.Count * compass.InnerRing.Speed
+		for i, move := range compass.Movement(step.RingGroup) {
+			locations[i] += step.Count * move
 		}
 	}
 
-	// 检查转动后的最终位置
-	if inner%SCALES != 0 || middle%SCALES != 0 || outer%SCALES != 0 {
-		return false, nil
+	// 检查转动后的最终位置是否都停在目标位置
+	return isSolved(compass, locations), nil
+}
+
+// isSolved 判断各圈是否都停在目标位置，locations 的下标与 Compass.Rings 一致
+func isSolved(compass Compass, locations []int) bool {
+	scales := compass.ScaleCount()
+	for i, location := range locations {
+		if Mod(location-compass.Rings[i].Target, scales) != 0 {
+			return false
+		}
 	}
-	return true, nil
+	return true
 }
@@ -0,0 +1,306 @@
+package ng
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// 角度表格中各行的名称
+const (
+	tableDirection = "转向"
+	tableAngle     = "转角"
+	tableLocation  = "起始位置"
+	tableTarget    = "目标位置"
+	tableScheme    = "方案"
+)
+
+// 转向，顺时针为正
+const (
+	tableClockwise        = "顺时针"
+	tableCounterclockwise = "逆时针"
+)
+
+// tableRingNames 角度表格中各圈的名称，下标与 Compass.Rings 一致
+var tableRingNames = [3]string{"内", "中", "外"}
+
+// tableSeparatorRegexp 匹配 Markdown 表格的分隔行中的单元格
+var tableSeparatorRegexp = regexp.MustCompile(`^:?-+:?$`)
+
+// ParseTable 解析 README 中描述经典三圈罗盘的 Markdown 角度表格，也就是游戏画面中玩家能直接读到的信息，例如:
+//
+//	|      | 内环    | 中环    | 外环    |
+//	|------|-------|-------|-------|
+//	| 转向   | 顺时针   | 逆时针   | 顺时针   |
+//	| 转角   | ∠120° | ∠180° | ∠180° |
+//	| 起始位置 | ∠0°   | ∠180° | ∠0°   |
+//
+//	|    | A   | B   | C   |
+//	|----|-----|-----|-----|
+//	| 方案 | 内+中 | 中+外 | 内+外 |
+//
+// 第一个表格的列是各圈，顺序任意，可以写作 内环、内圈 或 内；各行分别是转向（顺时针或逆时针）、
+// 每次转动的角度以及起始位置，还可以使用 目标位置 一行声明各圈需要停留的位置，未声明时为 ∠0°。
+// 第二个表格的 方案 一行按列出的顺序给出各个方案包含的圈，使用 + 连接。
+// 两个表格也可以合并为一个，表格之外的文字会被忽略。
+//
+// 角度按 scales 个刻度换算，每个刻度为 360° / scales，不是刻度整数倍的角度会被拒绝；
+// scales 为 0 时使用默认值 SCALES
+func ParseTable(text string, scales int) (Compass, error) {
+	compass := Compass{}
+	if scales == 0 {
+		scales = SCALES
+	}
+	if scales < 2 {
+		return compass, fmt.Errorf(`scales must be at least 2, got %d`, scales)
+	}
+	if scales != SCALES {
+		compass.Scales = scales
+	}
+
+	// 各圈的转向、转角、起始位置与目标位置，未声明时为 nil
+	values := make(map[string][]*int)
+	for _, key := range []string{tableDirection, tableAngle, tableLocation, tableTarget} {
+		values[key] = make([]*int, len(tableRingNames))
+	}
+
+	var header []string
+	for i, line := range strings.Split(text, "\n") {
+		number := i + 1
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "|") {
+			header = nil
+			continue
+		}
+		cells := splitTableRow(line)
+		if isTableSeparator(cells) {
+			continue
+		}
+		if header == nil {
+			header = cells
+			continue
+		}
+		if len(cells) != len(header) {
+			return compass, fmt.Errorf(`line %d: expected %d cells, got %d`, number, len(header), len(cells))
+		}
+
+		key := cells[0]
+		if key == tableScheme {
+			for _, cell := range cells[1:] {
+				rg, err := parseTableScheme(cell)
+				if err != nil {
+					return compass, fmt.Errorf(`line %d: %w`, number, err)
+				}
+				compass.RingGroups = append(compass.RingGroups, rg)
+			}
+			continue
+		}
+		row, ok := values[key]
+		if !ok {
+			return compass, fmt.Errorf(`line %d: unknown row "%s"`, number, key)
+		}
+		for i, cell := range cells[1:] {
+			index, ok := parseTableRing(header[i+1])
+			if !ok {
+				return compass, fmt.Errorf(`line %d: unknown ring "%s" in header`, number, header[i+1])
+			}
+			value, err := parseTableCell(key, cell, scales)
+			if err != nil {
+				return compass, fmt.Errorf(`line %d: %s of %s ring: %w`, number, key, RingName(index, 3), err)
+			}
+			row[index] = &value
+		}
+	}
+
+	compass.Rings = make([]Ring, len(tableRingNames))
+	for i := range compass.Rings {
+		for _, key := range []string{tableDirection, tableAngle, tableLocation} {
+			if values[key][i] == nil {
+				return compass, fmt.Errorf(`missing %s of %s ring`, key, RingName(i, 3))
+			}
+		}
+		compass.Rings[i] = Ring{
+			Location: *values[tableLocation][i],
+			Speed:    *values[tableDirection][i] * *values[tableAngle][i],
+		}
+		if target := values[tableTarget][i]; target != nil {
+			compass.Rings[i].Target = *target
+		}
+	}
+	if len(compass.RingGroups) == 0 {
+		return compass, fmt.Errorf(`missing %s`, tableScheme)
+	}
+	if err := compass.Validate(); err != nil {
+		return compass, fmt.Errorf(`invalid compass: %w`, err)
+	}
+	return compass, nil
+}
+
+// splitTableRow 拆分 Markdown 表格的一行，返回去掉首尾空白的各个单元格
+func splitTableRow(line string) []string {
+	line = strings.TrimPrefix(line, "|")
+	line = strings.TrimSuffix(line, "|")
+	cells := strings.Split(line, "|")
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	return cells
+}
+
+// isTableSeparator 判断表格的一行是否为表头与内容之间的分隔行
+func isTableSeparator(cells []string) bool {
+	for _, cell := range cells {
+		if !tableSeparatorRegexp.MatchString(cell) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseTableRing 解析圈的名称，例如 内环、内圈 或 内，返回圈在 Compass.Rings 中的下标
+func parseTableRing(name string) (int, bool) {
+	name = strings.TrimSuffix(strings.TrimSuffix(name, "环"), "圈")
+	for i, v := range tableRingNames {
+		if v == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// parseTableScheme 解析方案，例如 内+中
+func parseTableScheme(cell string) (RingGroup, error) {
+	var rg RingGroup
+	for _, name := range strings.Split(cell, "+") {
+		index, ok := parseTableRing(strings.TrimSpace(name))
+		if !ok {
+			return 0, fmt.Errorf(`unknown ring "%s" in scheme "%s"`, name, cell)
+		}
+		if rg.Contains(index) {
+			return 0, fmt.Errorf(`duplicated ring "%s" in scheme "%s"`, name, cell)
+		}
+		rg |= 1 << index
+	}
+	return rg, nil
+}
+
+// parseTableCell 解析单元格，转向返回 1 或 -1，其他各行返回换算后的刻度
+func parseTableCell(key, cell string, scales int) (int, error) {
+	if key == tableDirection {
+		switch cell {
+		case tableClockwise:
+			return 1, nil
+		case tableCounterclockwise:
+			return -1, nil
+		default:
+			return 0, fmt.Errorf(`direction must be %s or %s, got "%s"`, tableClockwise, tableCounterclockwise, cell)
+		}
+	}
+
+	text := strings.TrimSpace(strings.TrimPrefix(cell, "∠"))
+	text = strings.TrimSuffix(strings.TrimSuffix(text, "°"), "度")
+	degrees, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil {
+		return 0, fmt.Errorf(`invalid angle "%s"`, cell)
+	}
+	if degrees < 0 || degrees >= 360 {
+		return 0, fmt.Errorf(`angle %d° is out of range [0°, 360°)`, degrees)
+	}
+	if degrees*scales%360 != 0 {
+		return 0, fmt.Errorf(`angle %d° is not a multiple of a scale (360° / %d)`, degrees, scales)
+	}
+	notches := degrees * scales / 360
+	if key == tableAngle && notches == 0 {
+		return 0, fmt.Errorf(`angle must not be 0°`)
+	}
+	return notches, nil
+}
+
+// FormatTable 将经典三圈罗盘转换为 ParseTable 格式的 Markdown 角度表格
+// 总刻度值必须能整除 360°，方案不能声明自己的旋转速度；各圈都停在 ∠0° 时省略目标位置一行
+func FormatTable(compass Compass) (string, error) {
+	if err := compass.Validate(); err != nil {
+		return "", fmt.Errorf(`invalid compass: %w`, err)
+	}
+	if len(compass.Rings) != len(tableRingNames) {
+		return "", fmt.Errorf(`only compasses with %d rings can be formatted as a table, got %d`, len(tableRingNames), len(compass.Rings))
+	}
+	if len(compass.GroupSpeeds) > 0 {
+		return "", fmt.Errorf(`group speeds cannot be formatted as a table`)
+	}
+	scales := compass.ScaleCount()
+	if 360%scales != 0 {
+		return "", fmt.Errorf(`a scale of 360° / %d is not a whole degree`, scales)
+	}
+	degrees := func(notches int) string {
+		return fmt.Sprintf("∠%d°", Mod(notches, scales)*360/scales)
+	}
+
+	rings := [][]string{{""}, {tableDirection}, {tableAngle}, {tableLocation}}
+	targeted := false
+	for i, ring := range compass.Rings {
+		direction := tableClockwise
+		if ring.Speed < 0 {
+			direction = tableCounterclockwise
+		}
+		rings[0] = append(rings[0], tableRingNames[i]+"环")
+		rings[1] = append(rings[1], direction)
+		rings[2] = append(rings[2], degrees(Abs(ring.Speed)))
+		rings[3] = append(rings[3], degrees(ring.Location))
+		if Mod(ring.Target, scales) != 0 {
+			targeted = true
+		}
+	}
+	if targeted {
+		row := []string{tableTarget}
+		for _, ring := range compass.Rings {
+			row = append(row, degrees(ring.Target))
+		}
+		rings = append(rings, row)
+	}
+
+	schemes := [][]string{{""}, {tableScheme}}
+	for j, rg := range compass.RingGroups {
+		var names []string
+		for i, name := range tableRingNames {
+			if rg.Contains(i) {
+				names = append(names, name)
+			}
+		}
+		schemes[0] = append(schemes[0], string(rune('A'+j)))
+		schemes[1] = append(schemes[1], strings.Join(names, "+"))
+	}
+
+	return formatTable(rings) + "\n" + formatTable(schemes), nil
+}
+
+// formatTable 将第一行作为表头输出 Markdown 表格，各列按字符数对齐
+func formatTable(rows [][]string) string {
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	var sb strings.Builder
+	writeRow := func(row []string) {
+		sb.WriteString("|")
+		for i, cell := range row {
+			sb.WriteString(" " + cell + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)) + " |")
+		}
+		sb.WriteString("\n")
+	}
+	writeRow(rows[0])
+	sb.WriteString("|")
+	for _, width := range widths {
+		sb.WriteString(strings.Repeat("-", width+2) + "|")
+	}
+	sb.WriteString("\n")
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+	return sb.String()
+}
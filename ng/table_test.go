@@ -0,0 +1,165 @@
+package ng
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+func ExampleFormatTable() {
+	compass, err := ParseCompass("0+3,3-3,0+2/mi,om,oi")
+	if err != nil {
+		panic(err)
+	}
+	table, err := FormatTable(compass)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Print(table)
+	// Output:
+	// |      | 内环    | 中环    | 外环    |
+	// |------|-------|-------|-------|
+	// | 转向   | 顺时针   | 逆时针   | 顺时针   |
+	// | 转角   | ∠120° | ∠180° | ∠180° |
+	// | 起始位置 | ∠0°   | ∠180° | ∠0°   |
+	//
+	// |    | A   | B   | C   |
+	// |----|-----|-----|-----|
+	// | 方案 | 内+中 | 中+外 | 内+外 |
+}
+
+// TestTable_Golden 检查 FormatTable 的输出与 testdata/table 中的文件一致，并且可以被 ParseTable 还原
+// 使用 go test -update 重新生成这些文件
+func TestTable_Golden(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+	}{
+		{"readme-defect", "0+3,3-3,0+2/mi,om,oi"},
+		{"readme-matrix", "0+3,0-3,4-1/mi,om,oi"},
+		{"target", "0+2>3,3-3,1+3>5/mi,om,oi"},
+		{"scales-12", "0+5,3-3,11+1/o,mi@12"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			compass, err := ParseCompass(test.expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			table, err := FormatTable(compass)
+			if err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join("testdata", "table", test.name+".md")
+			if *update {
+				if err := os.WriteFile(path, []byte(table), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			golden, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if table != string(golden) {
+				t.Fatalf("unexpected table:\n%s\nexpected:\n%s", table, golden)
+			}
+
+			parsed, err := ParseTable(string(golden), compass.ScaleCount())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if parsed.String() != compass.String() {
+				t.Fatalf("unexpected compass %s (expected: %s)", parsed.String(), compass.String())
+			}
+		})
+	}
+}
+
+// TestParseTable_README 检查 README 中的表格与 testdata/table/readme-defect.md 一致
+func TestParseTable_README(t *testing.T) {
+	readme, err := os.ReadFile(filepath.Join("..", "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden, err := os.ReadFile(filepath.Join("testdata", "table", "readme-defect.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(readme), string(golden)) {
+		t.Fatal("the tables in README.md do not match testdata/table/readme-defect.md")
+	}
+}
+
+func TestParseTable(t *testing.T) {
+	// 列的顺序任意，表格可以合并，角度可以省略 ∠ 与 °
+	table := `
+罗盘的信息如下：
+
+| | 外圈 | 中 | 内环 |
+|:-:|:-:|:-:|:-:|
+| 起始位置 | 0 | 180 | ∠0° |
+| 转角 | 180° | ∠180° | 120 度 |
+| 转向 | 顺时针 | 逆时针 | 顺时针 |
+| 目标位置 | ∠180° | ∠0° | ∠0° |
+`
+	table += "\n| | A | B |\n|---|---|---|\n| 方案 | 外 + 中 | 内 |\n"
+	compass, err := ParseTable(table, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if compass.String() != "0+3>3,3-3,0+2/i,om" {
+		t.Fatalf("unexpected compass %s", compass.String())
+	}
+}
+
+func TestParseTable_Invalid(t *testing.T) {
+	header := "| | 内环 | 中环 | 外环 |\n|---|---|---|---|\n"
+	schemes := "\n| | A |\n|---|---|\n| 方案 | 内+中 |\n"
+	tests := []struct {
+		table  string
+		scales int
+	}{
+		{header + "| 转向 | 顺时针 | 逆时针 | 顺时针 |\n| 转角 | ∠90° | ∠180° | ∠180° |\n| 起始位置 | ∠0° | ∠0° | ∠0° |\n" + schemes, 6},                      // 角度不是刻度的整数倍
+		{header + "| 转向 | 顺时针 | 逆时针 | 顺时针 |\n| 转角 | ∠120° | ∠180° | ∠180° |\n| 起始位置 | ∠0° | ∠50° | ∠0° |\n" + schemes, 6},                    // 角度不是刻度的整数倍
+		{header + "| 转向 | 顺时针 | 逆时针 | 顺时针 |\n| 转角 | ∠0° | ∠180° | ∠180° |\n| 起始位置 | ∠0° | ∠0° | ∠0° |\n" + schemes, 6},                       // 转角为 0
+		{header + "| 转向 | 顺时针 | 逆时针 | 顺时针 |\n| 转角 | ∠120° | ∠180° | ∠180° |\n| 起始位置 | ∠360° | ∠0° | ∠0° |\n" + schemes, 6},                   // 角度超出范围
+		{header + "| 转向 | 向左 | 逆时针 | 顺时针 |\n| 转角 | ∠120° | ∠180° | ∠180° |\n| 起始位置 | ∠0° | ∠0° | ∠0° |\n" + schemes, 6},                      // 未知的转向
+		{header + "| 转向 | 顺时针 | 逆时针 | 顺时针 |\n| 转角 | ∠120° | ∠180° | ∠180° |\n" + schemes, 6},                                                 // 缺少起始位置
+		{header + "| 转向 | 顺时针 | 逆时针 | 顺时针 |\n| 转角 | ∠120° | ∠180° | ∠180° |\n| 起始位置 | ∠0° | ∠0° | ∠0° |\n", 6},                               // 缺少方案
+		{header + "| 转向 | 顺时针 | 逆时针 | 顺时针 |\n| 转角 | ∠120° | ∠180° | ∠180° |\n| 起始位置 | ∠0° | ∠0° | ∠0° |\n| 速度 | 1 | 2 | 3 |\n" + schemes, 6}, // 未知的行
+		{header + "| 转向 | 顺时针 | 逆时针 |\n" + schemes, 6},                  // 单元格数量不一致
+		{"| | 内环 | 上环 | 外环 |\n| 转向 | 顺时针 | 逆时针 | 顺时针 |\n" + schemes, 6}, // 未知的圈
+		{header + "| 转向 | 顺时针 | 逆时针 | 顺时针 |\n| 转角 | ∠120° | ∠180° | ∠180° |\n| 起始位置 | ∠0° | ∠0° | ∠0° |\n" + strings.Replace(schemes, "内+中", "内+内", 1), 6}, // 方案包含重复的圈
+		{header + "| 转向 | 顺时针 | 逆时针 | 顺时针 |\n| 转角 | ∠120° | ∠180° | ∠180° |\n| 起始位置 | ∠0° | ∠0° | ∠0° |\n" + schemes, 7},                                   // 刻度无法整除 360°
+		{header + "| 转向 | 顺时针 | 逆时针 | 顺时针 |\n| 转角 | ∠120° | ∠180° | ∠180° |\n| 起始位置 | ∠0° | ∠0° | ∠0° |\n" + schemes, 1},                                   // 刻度过少
+	}
+	for _, test := range tests {
+		if compass, err := ParseTable(test.table, test.scales); err == nil {
+			t.Errorf("expected error, got %s for:\n%s", compass.String(), test.table)
+		} else {
+			t.Log(err)
+		}
+	}
+}
+
+func TestFormatTable_Invalid(t *testing.T) {
+	expressions := []string{
+		"0+1,3-2,1+3,7+5/ab,cd@8",  // 不是三圈罗盘
+		"0+2,3-3,0+3/m+2i-1,om,oi", // 方案声明了旋转速度
+		"0+2,3-3,0+3/mi,om,oi@7",   // 刻度无法整除 360°
+	}
+	for _, expression := range expressions {
+		compass, err := ParseCompass(expression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if table, err := FormatTable(compass); err == nil {
+			t.Errorf("%s: expected error, got:\n%s", expression, table)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package ng
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Frame 罗盘在某一次转动之后的状态
+type Frame struct {
+	Press     int       // 第几次转动，初始状态为 0
+	RingGroup RingGroup // 本次转动的方案，初始状态为 0
+	Locations []int     // 各圈的位置，下标与 Compass.Rings 一致
+}
+
+// Trace 按顺序逐次转动罗盘的过程记录
+type Trace struct {
+	Compass Compass
+	Frames  []Frame // 第一帧是初始状态，之后每次转动记录一帧
+	// 第一个违反 Compass.Constraints 的转动，没有违反限制时为 nil
+	Violation *ConstraintViolation
+}
+
+// Simulate 按顺序逐次转动罗盘，记录每一次转动后各圈的位置
+// 每一步会转动 Step.Count 次，每次单独记录一帧，转动次数为 0 的步骤会被跳过；
+// 违反 Compass.Constraints 时仍然记录全部转动，并在 Trace.Violation 中指出第一个违反限制的转动
+func Simulate(compass Compass, steps Steps) (Trace, error) {
+	trace := Trace{Compass: compass}
+	if err := compass.Validate(); err != nil {
+		return trace, fmt.Errorf(`invalid compass, error: %w`, err)
+	}
+
+	scales := compass.ScaleCount()
+	locations := make([]int, len(compass.Rings))
+	for i, ring := range compass.Rings {
+		locations[i] = Mod(ring.Location, scales)
+	}
+	trace.Frames = append(trace.Frames, Frame{Locations: append([]int(nil), locations...)})
+
+	for i, step := range steps {
+		if step.Count < 0 {
+			return trace, fmt.Errorf(`step %d has a negative count: %d`, i, step.Count)
+		}
+		if step.Count > 0 && !compass.IsRingGroupSupported(step.RingGroup) {
+			return trace, fmt.Errorf(`steps contains an unexpected ring group, which is not supported by compass: %s (must be one of %v)`, step.RingGroup.Format(len(compass.Rings)), compass.RingGroups)
+		}
+
+		movement := compass.Movement(step.RingGroup)
+		for n := 0; n < step.Count; n++ {
+			for r, move := range movement {
+				locations[r] = Mod(locations[r]+move, scales)
+			}
+			trace.Frames = append(trace.Frames, Frame{
+				Press:     len(trace.Frames),
+				RingGroup: step.RingGroup,
+				Locations: append([]int(nil), locations...),
+			})
+		}
+	}
+
+	if !compass.Constraints.IsZero() {
+		if err := compass.Constraints.check(compass, steps); err != nil && !errors.As(err, &trace.Violation) {
+			return trace, err
+		}
+	}
+	return trace, nil
+}
+
+// Presses 返回转动的总次数
+func (t Trace) Presses() int {
+	return len(t.Frames) - 1
+}
+
+// Final 返回最后一次转动后各圈的位置
+func (t Trace) Final() []int {
+	if len(t.Frames) == 0 {
+		return nil
+	}
+	return t.Frames[len(t.Frames)-1].Locations
+}
+
+// Solved 判断最后一次转动后各圈是否都停在目标位置，并且转动过程中没有违反限制
+func (t Trace) Solved() bool {
+	if t.Violation != nil {
+		return false
+	}
+	final := t.Final()
+	if len(final) != len(t.Compass.Rings) {
+		return false
+	}
+	scales := t.Compass.ScaleCount()
+	for i, ring := range t.Compass.Rings {
+		if final[i] != Mod(ring.Target, scales) {
+			return false
+		}
+	}
+	return true
+}
+
+// String 转为逐行的文字表述，例如:
+//
+//	start: inner at 0, middle at 3, outer at 0
+//	press mi → inner at 2, middle at 0
+func (t Trace) String() string {
+	rings := len(t.Compass.Rings)
+	lines := make([]string, 0, len(t.Frames))
+	for _, frame := range t.Frames {
+		positions := make([]string, 0, rings)
+		for i, location := range frame.Locations {
+			// 转动时只列出本次转动的圈
+			if frame.Press > 0 && !frame.RingGroup.Contains(i) {
+				continue
+			}
+			positions = append(positions, fmt.Sprintf("%s at %d", RingName(i, rings), location))
+		}
+		if frame.Press == 0 {
+			lines = append(lines, "start: "+strings.Join(positions, ", "))
+		} else {
+			lines = append(lines, fmt.Sprintf("press %s → %s", frame.RingGroup.Format(rings), strings.Join(positions, ", ")))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// traceJSON Trace 的 JSON 表述
+type traceJSON struct {
+	Compass string      `json:"compass"`
+	Rings   []string    `json:"rings"`
+	Frames  []frameJSON `json:"frames"`
+	Solved  bool        `json:"solved"`
+	// 违反限制时的说明，参见 Trace.Violation
+	Violation string `json:"violation,omitempty"`
+}
+
+// frameJSON Frame 的 JSON 表述
+type frameJSON struct {
+	Press     int    `json:"press"`
+	RingGroup string `json:"ringGroup,omitempty"`
+	Locations []int  `json:"locations"`
+}
+
+// MarshalJSON 实现 json.Marshaler 接口
+// 各圈的名称与位置都按 Compass.Rings 的顺序（从内到外）排列
+func (t Trace) MarshalJSON() ([]byte, error) {
+	rings := len(t.Compass.Rings)
+	content := traceJSON{
+		Compass: t.Compass.String(),
+		Rings:   make([]string, rings),
+		Frames:  make([]frameJSON, len(t.Frames)),
+		Solved:  t.Solved(),
+	}
+	if t.Violation != nil {
+		content.Violation = t.Violation.Error()
+	}
+	for i := range content.Rings {
+		content.Rings[i] = RingName(i, rings)
+	}
+	for i, frame := range t.Frames {
+		content.Frames[i] = frameJSON{
+			Press:     frame.Press,
+			RingGroup: frame.RingGroup.Format(rings),
+			Locations: frame.Locations,
+		}
+	}
+	return json.Marshal(content)
+}
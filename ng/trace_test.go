@@ -0,0 +1,118 @@
+package ng
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func ExampleSimulate() {
+	compass, err := ParseCompass("0+3,3-3,0+2/mi,om,oi")
+	if err != nil {
+		panic(err)
+	}
+	trace, err := Simulate(compass, Steps{{RingGroup: OuterMiddle, Count: 1}, {RingGroup: MiddleInner, Count: 2}})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(trace.String())
+	fmt.Println(trace.Presses(), trace.Solved())
+	// Output:
+	// start: inner at 0, middle at 3, outer at 0
+	// press om → middle at 0, outer at 3
+	// press mi → inner at 2, middle at 3
+	// press mi → inner at 4, middle at 0
+	// 3 false
+}
+
+func TestSimulate(t *testing.T) {
+	compass, err := ParseCompass("5+1>2,6-2,7+3>7,4+5/ab,dc,ad,b@8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	solution := Steps{{RingGroup: 0b0100, Count: 2}, {RingGroup: 0b1001, Count: 4}, {RingGroup: 0b1100, Count: 1}}
+	trace, err := Simulate(compass, solution)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trace.Presses() != solution.Total() || len(trace.Frames) != solution.Total()+1 {
+		t.Fatalf("unexpected frame count: %d", len(trace.Frames))
+	}
+	ok, err := CheckSolution(compass, solution)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trace.Solved() != ok {
+		t.Fatalf("Simulate and CheckSolution disagree: %v != %v", trace.Solved(), ok)
+	}
+
+	content, err := json.Marshal(trace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Compass string   `json:"compass"`
+		Rings   []string `json:"rings"`
+		Frames  []struct {
+			Press     int    `json:"press"`
+			RingGroup string `json:"ringGroup"`
+			Locations []int  `json:"locations"`
+		} `json:"frames"`
+		Solved bool `json:"solved"`
+	}
+	if err := json.Unmarshal(content, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Compass != compass.String() || fmt.Sprint(decoded.Rings) != "[d c b a]" || !decoded.Solved {
+		t.Fatalf("unexpected JSON: %s", content)
+	}
+	if last := decoded.Frames[len(decoded.Frames)-1]; last.Press != 7 || last.RingGroup != "ab" || fmt.Sprint(last.Locations) != "[0 7 0 2]" {
+		t.Fatalf("unexpected last frame: %+v", last)
+	}
+}
+
+func TestSimulate_Invalid(t *testing.T) {
+	compass, err := ParseCompass("0+3,3-3,0+2/mi,om,oi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Simulate(compass, Steps{{RingGroup: Outer, Count: 1}}); err == nil {
+		t.Error("expected error for unsupported ring group")
+	}
+	if _, err := Simulate(compass, Steps{{RingGroup: MiddleInner, Count: -1}}); err == nil {
+		t.Error("expected error for negative count")
+	}
+}
+
+func TestSimulate_Constraints(t *testing.T) {
+	compass, err := ParseCompass("0+3,3-3,0+2/mi,om,oi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	compass.Constraints = Constraints{MaxGroupPresses: map[RingGroup]int{MiddleInner: 1}}
+
+	// 各圈最终停在目标位置，但是第 2 次转动违反了限制
+	trace, err := Simulate(compass, Steps{{RingGroup: MiddleInner, Count: 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trace.Presses() != 3 || trace.Solved() || trace.Violation == nil || trace.Violation.Press != 2 {
+		t.Fatalf("unexpected trace %+v", trace)
+	}
+	data, err := json.Marshal(trace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"violation":"press 2 exceeds the limit of 1 presses for ring group mi"`) {
+		t.Fatalf("unexpected JSON %s", data)
+	}
+
+	trace, err = Simulate(compass, Steps{{RingGroup: MiddleInner, Count: 1}, {RingGroup: OuterInner, Count: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !trace.Solved() || trace.Violation != nil {
+		t.Fatalf("unexpected trace %+v", trace)
+	}
+}
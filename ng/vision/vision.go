@@ -0,0 +1,363 @@
+// Package vision 从罗盘界面的截图中读取罗盘，省去手动输入罗盘信息表达式的麻烦
+//
+// 截图需要符合 ng/render 绘制的参考界面：各圈是同心圆环，与 ng.Ring.Location 一致，刻度 0 位于正左方，
+// 每个圈上有实心指针、空心的目标位置标记以及表示转动方向与角度的弧形箭头，下方一行是方案按钮，
+// 按钮下的圆点按 render.RingColor 的颜色表示方案转动的圈，参见 render.Geometry。
+// 参考界面的所有元素都按图片宽度等比例缩放，因此任意分辨率的截图都可以读取，只要宽度不小于 MinWidthOf 的要求，
+// 底部带有说明文字的 render.WalkthroughPNGs 等图片也可以读取；刻度的数量从外圈外侧的刻度线数出。
+//
+// 读取只依赖像素的颜色与位置，不做文字识别，因此无法读出方案自己的旋转速度（参见 ng.Compass.GroupSpeeds），
+// 转动过程中的过渡帧也无法读取。
+//
+// 注意：目前只能读取 ng/render 绘制的参考界面，不能读取游戏内的截图。游戏内的界面有背景、光效与不同的按钮布局，
+// 需要用真实的游戏截图标定至少一种参考分辨率下各元素的位置与颜色，仓库中还没有这样的截图，因此没有提供游戏内的参考界面，
+// 对游戏内的截图通常会返回 ErrUnrecognized
+package vision
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/AyakuraYuki/go-starrail-compass/ng"
+	"github.com/AyakuraYuki/go-starrail-compass/ng/render"
+)
+
+// MinWidth 能够读取的经典三圈罗盘截图的最小宽度，单位为像素，其他圈数参见 MinWidthOf
+const MinWidth = 180
+
+// ErrUnrecognized 截图不符合参考界面，或者无法在截图中找到需要的元素
+var ErrUnrecognized = errors.New(`unrecognized compass screenshot`)
+
+// Reference 截图的参考界面
+type Reference struct {
+	Rings  int // 圈数，0 表示经典三圈罗盘
+	Scales int // 总刻度值，0 表示从截图中的刻度线数出
+}
+
+// Read 按参考界面读取截图中的罗盘，返回的罗盘已经通过 ng.Compass.Validate 检查
+// 返回的 RingGroups 按方案按钮从左到右的顺序排列，与绘制时的顺序一致；
+// 注意 ng.Compass.String 会对方案排序，比较顺序时需要直接比较 RingGroups
+func Read(img image.Image, ref Reference) (ng.Compass, error) {
+	compass := ng.Compass{}
+	rings := ref.Rings
+	if rings == 0 {
+		rings = 3
+	}
+	if rings < 1 || rings > ng.MaxRings {
+		return compass, fmt.Errorf(`rings must be between 1 and %d, got %d`, ng.MaxRings, rings)
+	}
+
+	bounds := img.Bounds()
+	if minWidth := MinWidthOf(rings); bounds.Dx() < minWidth {
+		return compass, fmt.Errorf(`%w: width %d is less than %d for %d rings`, ErrUnrecognized, bounds.Dx(), minWidth, rings)
+	}
+	g := render.GeometryOf(rings, render.Options{Size: bounds.Dx()})
+	if float64(bounds.Dy()) < g.Height-2 || float64(bounds.Dy()) > g.Height*1.25 {
+		return compass, fmt.Errorf(`%w: expected a height of about %.0f for width %d, got %d`, ErrUnrecognized, g.Height, bounds.Dx(), bounds.Dy())
+	}
+	s := &screenshot{img: img, geometry: g, rings: rings}
+	s.background = s.at(1, 1)
+
+	scales := ref.Scales
+	if scales == 0 {
+		scales = s.countTicks()
+	}
+	if scales < 2 {
+		return compass, fmt.Errorf(`%w: found %d ticks`, ErrUnrecognized, scales)
+	}
+	s.scales = scales
+	if scales != ng.SCALES {
+		compass.Scales = scales
+	}
+
+	compass.Rings = make([]ng.Ring, rings)
+	for i := range compass.Rings {
+		ring, err := s.ring(i)
+		if err != nil {
+			return compass, fmt.Errorf(`%w: %s ring: %v`, ErrUnrecognized, ng.RingName(i, rings), err)
+		}
+		compass.Rings[i] = ring
+	}
+
+	ringGroups, err := s.buttons()
+	if err != nil {
+		return compass, fmt.Errorf(`%w: %v`, ErrUnrecognized, err)
+	}
+	compass.RingGroups = ringGroups
+
+	if err := compass.Validate(); err != nil {
+		return compass, fmt.Errorf(`invalid compass: %w`, err)
+	}
+	return compass, nil
+}
+
+// MinWidthOf 返回能够读取的 rings 个圈的罗盘截图的最小宽度，单位为像素
+// 圈数越多每个圈越窄，线条也越细，因此要求每个圈的宽度不小于三圈罗盘在 MinWidth 下的宽度
+func MinWidthOf(rings int) int {
+	band := render.GeometryOf(3, render.Options{Size: MinWidth}).Band
+	width := MinWidth
+	for render.GeometryOf(rings, render.Options{Size: width}).Band < band {
+		width++
+	}
+	return width
+}
+
+// screenshot 读取中的截图
+type screenshot struct {
+	img        image.Image
+	geometry   render.Geometry
+	rings      int
+	scales     int
+	background color.RGBA
+}
+
+// at 返回 (x, y) 处的像素，超出图片的坐标取最近的像素
+func (s *screenshot) at(x, y float64) color.RGBA {
+	bounds := s.img.Bounds()
+	px := min(max(bounds.Min.X+int(math.Floor(x)), bounds.Min.X), bounds.Max.X-1)
+	py := min(max(bounds.Min.Y+int(math.Floor(y)), bounds.Min.Y), bounds.Max.Y-1)
+	return color.RGBAModel.Convert(s.img.At(px, py)).(color.RGBA)
+}
+
+// inked 判断 (x, y) 附近是否有深色的线条，邻域的大小随图片等比例缩放，用于应对抗锯齿与缩放造成的模糊
+func (s *screenshot) inked(x, y float64) bool {
+	return s.darkest(x, y) < 120
+}
+
+// darkest 返回 (x, y) 附近最暗的像素的亮度，忽略指针等使用圈的颜色的像素，部分圈的颜色与线条一样暗
+func (s *screenshot) darkest(x, y float64) float64 {
+	radius := max(1, int(math.Round(s.geometry.Band*0.02)))
+	darkest := math.Inf(1)
+	for dy := -radius; dy <= radius; dy++ {
+		for dx := -radius; dx <= radius; dx++ {
+			if c := s.at(x+float64(dx), y+float64(dy)); s.ringColorIndex(c) < 0 {
+				darkest = math.Min(darkest, luminance(c))
+			}
+		}
+	}
+	return darkest
+}
+
+// polar 返回与圆心距离为 radius、角度为 angle 的点，正左方为 0，顺时针为正
+func (s *screenshot) polar(radius, angle float64) (float64, float64) {
+	return s.geometry.CenterX - radius*math.Cos(angle), s.geometry.CenterY - radius*math.Sin(angle)
+}
+
+// notchAngle 返回一个刻度对应的角度
+func (s *screenshot) notchAngle() float64 {
+	return 2 * math.Pi / float64(s.scales)
+}
+
+// countTicks 沿着外圈外侧的刻度线所在的圆周数出刻度线的数量
+func (s *screenshot) countTicks() int {
+	g := s.geometry
+	outer := g.Radii[len(g.Radii)-1] + g.Band/2
+	radius := outer + (g.Band*0.05+g.Width*0.03)/2
+	samples := int(2 * math.Pi * radius * 2)
+	ticks := 0
+	// 从刻度 0 的刻度线之后开始，避免把同一条刻度线数两次
+	previous := true
+	for k := 1; k <= samples; k++ {
+		x, y := s.polar(radius, 2*math.Pi*(float64(k)/float64(samples)+0.5/float64(samples)))
+		current := s.darkest(x, y) < 160
+		if current && !previous {
+			ticks++
+		}
+		previous = current
+	}
+	return ticks
+}
+
+// ring 读取下标为 index 的圈的位置、旋转速度与目标位置
+func (s *screenshot) ring(index int) (ng.Ring, error) {
+	location, err := s.pointer(index)
+	if err != nil {
+		return ng.Ring{}, err
+	}
+	speed, err := s.arrow(index, location)
+	if err != nil {
+		return ng.Ring{}, err
+	}
+	target, err := s.target(index, location, speed)
+	if err != nil {
+		return ng.Ring{}, err
+	}
+	return ng.Ring{Location: location, Speed: speed, Target: target}, nil
+}
+
+// pointer 找出指针所在的刻度，指针是圈的中线上颜色为 render.RingColor 的圆点
+func (s *screenshot) pointer(index int) (int, error) {
+	radius := s.geometry.Radii[index]
+	location := -1
+	for notch := 0; notch < s.scales; notch++ {
+		x, y := s.polar(radius, s.notchAngle()*float64(notch))
+		if s.ringColorIndex(s.at(x, y)) != index {
+			continue
+		}
+		if location >= 0 {
+			return 0, fmt.Errorf(`pointers found at %d and %d`, location, notch)
+		}
+		location = notch
+	}
+	if location < 0 {
+		return 0, errors.New(`no pointer found`)
+	}
+	return location, nil
+}
+
+// arrow 沿着圈的中线从指针向两侧寻找弧形箭头，返回旋转速度
+// 箭头从指针旁边开始，在终点处指向转动一次后的刻度，与指针两侧留出的间隔相等，因此转过的角度为箭头起点与终点的角度之和
+func (s *screenshot) arrow(index, location int) (int, error) {
+	radius := s.geometry.Radii[index]
+	step := 0.5 / radius
+	start := s.notchAngle() * float64(location)
+
+	best, speed := math.Inf(1), 0
+	for _, direction := range []float64{1, -1} {
+		from, to := -1.0, -1.0
+		misses := 0
+		for t := step; t < 2*math.Pi-step; t += step {
+			x, y := s.polar(radius, start+direction*t)
+			if !s.inked(x, y) {
+				if from >= 0 {
+					if misses++; misses > 3 {
+						if !s.markerEdge(radius, start+direction*(from+to)/2, to-from) {
+							break
+						}
+						from, to = -1, -1
+					}
+				}
+				continue
+			}
+			if from < 0 {
+				from = t
+			}
+			to, misses = t, 0
+		}
+		if from < 0 || from >= best || s.markerEdge(radius, start+direction*(from+to)/2, to-from) {
+			continue
+		}
+		best = from
+		speed = int(direction) * int(math.Round((from+to)/s.notchAngle()))
+	}
+	if speed == 0 {
+		return 0, errors.New(`no speed arrow found`)
+	}
+	return speed, nil
+}
+
+// markerEdge 判断圈的中线上以 angle 为中点、长度为 length 的深色线段是否只是目标位置标记与中线相交的部分
+func (s *screenshot) markerEdge(radius, angle, length float64) bool {
+	// 标记的边线宽度加上 inked 的邻域
+	tolerance := (s.geometry.Knob*0.25 + 2*max(1, math.Round(s.geometry.Band*0.02)) + 2) / radius
+	if length > tolerance {
+		return false
+	}
+	offset := math.Mod(angle, s.notchAngle())
+	if offset < 0 {
+		offset += s.notchAngle()
+	}
+	// 与最近的刻度之间的距离
+	offset = math.Min(offset, s.notchAngle()-offset)
+	return math.Abs(offset-s.geometry.Knob*1.3/radius) <= tolerance
+}
+
+// target 找出目标位置标记所在的刻度，标记是围绕刻度的空心圆
+// 标记与指针重合时，标记的径向两侧被指针遮住，改为检查背向箭头一侧的两个斜向点
+func (s *screenshot) target(index, location, speed int) (int, error) {
+	radius := s.geometry.Radii[index]
+	marker := s.geometry.Knob * 1.3
+	target := -1
+	for notch := 0; notch < s.scales; notch++ {
+		angle := s.notchAngle() * float64(notch)
+		var found bool
+		if notch == location {
+			direction := 1.0
+			if speed > 0 {
+				direction = -1
+			}
+			x, y := s.polar(radius, angle)
+			// 切线方向与径向方向的分量
+			tx, ty := direction*math.Sin(angle)*marker/math.Sqrt2, -direction*math.Cos(angle)*marker/math.Sqrt2
+			rx, ry := -math.Cos(angle)*marker/math.Sqrt2, -math.Sin(angle)*marker/math.Sqrt2
+			found = s.inked(x+tx+rx, y+ty+ry) && s.inked(x+tx-rx, y+ty-ry)
+		} else {
+			x1, y1 := s.polar(radius-marker, angle)
+			x2, y2 := s.polar(radius+marker, angle)
+			found = s.inked(x1, y1) && s.inked(x2, y2)
+		}
+		if !found {
+			continue
+		}
+		if target >= 0 {
+			return 0, fmt.Errorf(`target markers found at %d and %d`, target, notch)
+		}
+		target = notch
+	}
+	if target < 0 {
+		return 0, errors.New(`no target marker found`)
+	}
+	return target, nil
+}
+
+// buttons 从左到右读取方案按钮，按钮之间以背景色分隔，按钮下方圆点的颜色表示方案转动的圈
+func (s *screenshot) buttons() ([]ng.RingGroup, error) {
+	g := s.geometry
+	top := g.ButtonTop + g.ButtonHeight*0.1
+	dots := g.ButtonTop + g.ButtonHeight*0.75
+	width := int(g.Width)
+
+	var ringGroups []ng.RingGroup
+	for x := 0; x < width; {
+		if distance(s.at(float64(x), top), s.background) < 24 {
+			x++
+			continue
+		}
+		end := x
+		for end < width && distance(s.at(float64(end), top), s.background) >= 24 {
+			end++
+		}
+		if float64(end-x) >= g.ButtonHeight*0.5 {
+			var rg ng.RingGroup
+			for dx := x; dx < end; dx++ {
+				if i := s.ringColorIndex(s.at(float64(dx), dots)); i >= 0 {
+					rg |= 1 << i
+				}
+			}
+			if rg == 0 {
+				return nil, fmt.Errorf(`button %d does not show any ring`, len(ringGroups)+1)
+			}
+			ringGroups = append(ringGroups, rg)
+		}
+		x = end
+	}
+	if len(ringGroups) == 0 {
+		return nil, errors.New(`no buttons found`)
+	}
+	return ringGroups, nil
+}
+
+// ringColorIndex 返回与颜色最接近的 render.RingColor 的下标，颜色与所有圈的颜色都相差较远时返回 -1
+func (s *screenshot) ringColorIndex(c color.RGBA) int {
+	index, best := -1, 40.0
+	for i := 0; i < s.rings; i++ {
+		if d := distance(c, render.RingColor(i)); d < best {
+			index, best = i, d
+		}
+	}
+	return index
+}
+
+// distance 返回两个颜色在 RGB 空间中的距离
+func distance(a, b color.RGBA) float64 {
+	dr, dg, db := float64(a.R)-float64(b.R), float64(a.G)-float64(b.G), float64(a.B)-float64(b.B)
+	return math.Sqrt(dr*dr + dg*dg + db*db)
+}
+
+// luminance 返回颜色的亮度
+func luminance(c color.RGBA) float64 {
+	return 0.299*float64(c.R) + 0.587*float64(c.G) + 0.114*float64(c.B)
+}
@@ -0,0 +1,187 @@
+package vision
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/png"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AyakuraYuki/go-starrail-compass/ng"
+	"github.com/AyakuraYuki/go-starrail-compass/ng/render"
+)
+
+func ExampleRead() {
+	compass, err := ng.ParseCompass("0+3,3-3,0+2/mi,om,oi")
+	if err != nil {
+		panic(err)
+	}
+	img := render.RenderImage(compass, render.Options{Size: 240})
+
+	got, err := Read(img, Reference{})
+	if err != nil {
+		panic(err)
+	}
+	// String 会对方案排序，RingGroups 则保持方案按钮从左到右的顺序
+	fmt.Println(got.String())
+	names := make([]string, len(got.RingGroups))
+	for i, rg := range got.RingGroups {
+		names[i] = rg.Format(len(got.Rings))
+	}
+	fmt.Println(strings.Join(names, ","))
+	// Output:
+	// 0+3,3-3,0+2/mi,oi,om
+	// mi,om,oi
+}
+
+func TestRead(t *testing.T) {
+	tests := []string{
+		"0+3,3-3,0+2/mi,om,oi",
+		"0+2>3,3-3,1+3>5/mi,om,oi",
+		"1-1,2+5,4-5/o,m,i,oi",
+		"5+1>2,6-2,7+3>7,4+5/ab,dc,ad,b@8",
+		"0+5,3-3,1+2/o,mi@12",
+		"2+1,0+3,7-2,5+4,3-1/ab,ce,d,bcd@8",
+	}
+	for _, expr := range tests {
+		compass, err := ng.ParseCompass(expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rings := len(compass.Rings)
+		for _, size := range []int{MinWidthOf(rings), 240, 360, 500, 1080} {
+			if size < MinWidthOf(rings) {
+				continue
+			}
+			got, err := Read(render.RenderImage(compass, render.Options{Size: size}), Reference{Rings: rings})
+			if err != nil {
+				t.Errorf("%s @%d: %v", expr, size, err)
+				continue
+			}
+			if got.String() != compass.String() || !reflect.DeepEqual(got.RingGroups, compass.RingGroups) {
+				t.Errorf("%s @%d: got %s (ring groups %v)", expr, size, got.String(), got.RingGroups)
+			}
+		}
+	}
+}
+
+func TestRead_Scales(t *testing.T) {
+	compass, err := ng.ParseCompass("5+1>2,6-2,7+3>7,4+5/ab,dc,ad,b@8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := render.RenderImage(compass, render.Options{Size: 300})
+	// 指定的总刻度值优先于截图中的刻度线
+	got, err := Read(img, Reference{Rings: 4, Scales: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != compass.String() {
+		t.Fatalf("got %s", got.String())
+	}
+	if _, err := Read(img, Reference{Rings: 4, Scales: 6}); err == nil {
+		t.Fatal("expected error for mismatched scales")
+	}
+}
+
+func TestRead_Walkthrough(t *testing.T) {
+	compass, err := ng.ParseCompass("0+3,3-3,0+2/mi,om,oi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trace, err := ng.Simulate(compass, ng.Steps{{RingGroup: ng.OuterMiddle, Count: 1}, {RingGroup: ng.MiddleInner, Count: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// expected 返回第 i 帧的罗盘
+	expected := func(i int) string {
+		c := compass
+		c.Rings = append([]ng.Ring(nil), compass.Rings...)
+		for r, location := range trace.Frames[i].Locations {
+			c.Rings[r].Location = location
+		}
+		return c.String()
+	}
+
+	// 底部带有说明文字，并且高亮了正在转动的方案按钮
+	images, err := render.WalkthroughPNGs(trace, render.Options{Size: 300})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, data := range images {
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := Read(img, Reference{})
+		if err != nil {
+			t.Fatalf("frame %d: %v", i, err)
+		}
+		if got.String() != expected(i) {
+			t.Errorf("frame %d: expected %s, got %s", i, expected(i), got.String())
+		}
+	}
+
+	// GIF 的各帧使用调色板，最后一帧是最后一个状态
+	data, err := render.WalkthroughGIF(trace, render.WalkthroughOptions{Options: render.Options{Size: 300}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	animation, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Read(animation.Image[len(animation.Image)-1], Reference{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := len(trace.Frames) - 1; got.String() != expected(last) {
+		t.Fatalf("expected %s, got %s", expected(last), got.String())
+	}
+}
+
+func TestRead_Unrecognized(t *testing.T) {
+	compass, err := ng.ParseCompass("0+3,3-3,0+2/mi,om,oi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	blank := render.RenderImage(compass, render.Options{Size: 240})
+	for i := range blank.Pix {
+		blank.Pix[i] = 0xff
+	}
+	tests := []struct {
+		name string
+		img  image.Image
+		ref  Reference
+	}{
+		{"too narrow", render.RenderImage(compass, render.Options{Size: MinWidth - 20}), Reference{}},
+		{"too narrow for the rings", render.RenderImage(compass, render.Options{Size: 200}), Reference{Rings: 5}},
+		{"wrong aspect ratio", image.NewRGBA(image.Rect(0, 0, 240, 240)), Reference{}},
+		{"blank", blank, Reference{}},
+		{"wrong rings", render.RenderImage(compass, render.Options{Size: 240}), Reference{Rings: 4}},
+	}
+	for _, test := range tests {
+		if _, err := Read(test.img, test.ref); !errors.Is(err, ErrUnrecognized) {
+			t.Errorf("%s: expected ErrUnrecognized, got %v", test.name, err)
+		}
+	}
+
+	if _, err := Read(blank, Reference{Rings: ng.MaxRings + 1}); err == nil || errors.Is(err, ErrUnrecognized) {
+		t.Fatalf("expected error for invalid rings, got %v", err)
+	}
+}
+
+func TestMinWidthOf(t *testing.T) {
+	if MinWidthOf(1) != MinWidth || MinWidthOf(3) != MinWidth {
+		t.Fatalf("unexpected min width %d, %d", MinWidthOf(1), MinWidthOf(3))
+	}
+	for rings := 4; rings <= ng.MaxRings; rings++ {
+		if MinWidthOf(rings) <= MinWidthOf(rings-1) {
+			t.Fatalf("min width of %d rings should be greater than that of %d rings", rings, rings-1)
+		}
+	}
+}